@@ -97,6 +97,16 @@ func SumDistinct(col string) Aggregate {
 
 func (Aggregate) selected() {}
 
+// ASC 用于 ORDER BY，例如 ORDER BY COUNT(`id`) ASC
+func (a Aggregate) ASC() OrderBy {
+	return OrderBy{expr: a, order: "ASC"}
+}
+
+// DESC 用于 ORDER BY，例如 ORDER BY COUNT(`id`) DESC
+func (a Aggregate) DESC() OrderBy {
+	return OrderBy{expr: a, order: "DESC"}
+}
+
 func (a Aggregate) EQ(val interface{}) Predicate {
 	return Predicate{
 		left:  a,
@@ -23,6 +23,12 @@ type Assignable interface {
 type Assignment binaryExpr
 
 func Assign(column string, value interface{}) Assignment {
+	return C(column).Assign(value)
+}
+
+// Assign 和 Assign 函数一样，但是可以使用 Table.C 指定列所属的表，
+// 一般用于多表更新，例如 UPDATE a JOIN b ON ... SET a.x = b.y
+func (c Column) Assign(value interface{}) Assignment {
 	var expr Expr
 	switch v := value.(type) {
 	case Expr:
@@ -30,7 +36,7 @@ func Assign(column string, value interface{}) Assignment {
 	default:
 		expr = valueExpr{val: v}
 	}
-	return Assignment{left: C(column), op: opEQ, right: expr}
+	return Assignment{left: c, op: opEQ, right: expr}
 }
 
 func (Assignment) assign() {
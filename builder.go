@@ -17,7 +17,9 @@ package eorm
 import (
 	"context"
 	"database/sql"
+	"strings"
 
+	"github.com/ecodeclub/eorm/internal/dialect"
 	"github.com/ecodeclub/eorm/internal/errs"
 	"github.com/ecodeclub/eorm/internal/model"
 	"github.com/ecodeclub/eorm/internal/query"
@@ -80,7 +82,7 @@ func (q Querier[T]) Exec(ctx context.Context) Result {
 	for i := len(ms) - 1; i >= 0; i-- {
 		handler = ms[i](handler)
 	}
-	qr := handler(ctx, q.qc)
+	qr := handler(ctx, q.withComment(ctx, q.qc))
 	var res sql.Result
 	if qr.Result != nil {
 		res = qr.Result.(sql.Result)
@@ -106,13 +108,23 @@ type builder struct {
 	buffer *bytebufferpool.ByteBuffer
 	meta   *model.TableMeta
 	args   []interface{}
+	// argOffset 是作为子查询构造的时候，外部查询已经有的参数个数
+	// 像 PostgreSQL 这种使用 $1 作为占位符的方言，需要依赖它来计算序号
+	argOffset int
 	// aliases map[string]struct{}
 }
 
+// argOffsetSetter 用于在构造子查询之前，设置其参数的起始序号
+type argOffsetSetter interface {
+	setArgOffset(offset int)
+}
+
+func (b *builder) setArgOffset(offset int) {
+	b.argOffset = offset
+}
+
 func (b *builder) quote(val string) {
-	b.writeByte(b.dialect.Quote)
-	b.writeString(val)
-	b.writeByte(b.dialect.Quote)
+	b.writeString(b.dialect.Quote(val))
 }
 
 func (b *builder) space() {
@@ -144,8 +156,82 @@ func (b *builder) parameter(arg interface{}) {
 		// TODO 4 may be not a good number
 		b.args = make([]interface{}, 0, 4)
 	}
-	b.writeByte('?')
 	b.args = append(b.args, arg)
+	b.writeString(b.dialect.Placeholder(b.argOffset + len(b.args)))
+}
+
+// buildLimitOffset 交给方言来生成分页子句
+func (b *builder) buildLimitOffset(limit, offset int) {
+	b.dialect.BuildLimitOffset(dialectWriter{b: b}, limit, offset)
+}
+
+// buildReturning 构造 RETURNING 子句，fields 是字段名
+func (b *builder) buildReturning(fields []string) error {
+	cs := make([]string, 0, len(fields))
+	for _, f := range fields {
+		fd, ok := b.meta.FieldMap[f]
+		if !ok {
+			return errs.NewInvalidFieldError(f)
+		}
+		cs = append(cs, fd.ColumnName)
+	}
+	return b.dialect.BuildReturning(dialectWriter{b: b}, cs)
+}
+
+// buildLock 生成行锁子句
+func (b *builder) buildLock(lock dialect.Lock) error {
+	if lock.Strength == dialect.LockNone {
+		if lock.Wait != dialect.LockWaitDefault {
+			return errs.ErrLockWaitWithoutLock
+		}
+		return nil
+	}
+	return b.dialect.BuildLock(dialectWriter{b: b}, lock)
+}
+
+// buildHints 生成位于 SELECT 之后的优化器提示，例如 /*+ MAX_EXECUTION_TIME(1000) */
+// 优化器提示本身是注释，不认识它的数据库会直接忽略，所以不需要区分方言，
+// 但是提示中不能出现 */，否则后面的内容会被当成 SQL 执行
+func (b *builder) buildHints(hints []string) error {
+	b.writeString("/*+ ")
+	for i, h := range hints {
+		if strings.Contains(h, "*/") {
+			return errs.NewInvalidHintError(h)
+		}
+		if i > 0 {
+			b.space()
+		}
+		b.writeString(h)
+	}
+	b.writeString(" */ ")
+	return nil
+}
+
+// buildIndexHints 生成位于表名之后的索引提示
+func (b *builder) buildIndexHints(hints []dialect.IndexHint) error {
+	for _, h := range hints {
+		if len(h.Indexes) == 0 {
+			return errs.ErrIndexHintWithoutIndex
+		}
+		if err := b.dialect.BuildIndexHint(dialectWriter{b: b}, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dialectWriter 将 builder 适配为 dialect.Writer，
+// 避免 builder 暴露公开方法
+type dialectWriter struct {
+	b *builder
+}
+
+func (w dialectWriter) WriteString(val string) {
+	w.b.writeString(val)
+}
+
+func (w dialectWriter) WriteArg(arg any) {
+	w.b.parameter(arg)
 }
 
 func (b *builder) buildExpr(expr Expr) error {
@@ -180,6 +266,12 @@ func (b *builder) buildExpr(expr Expr) error {
 		}
 	case Subquery:
 		return b.buildSubquery(e, false)
+	case CaseExpr:
+		return b.buildCase(e)
+	case Window:
+		return b.buildWindow(e)
+	case FuncExpr:
+		return b.buildFunc(e)
 	case SubqueryExpr:
 		b.writeString(e.pred)
 		b.writeByte(' ')
@@ -205,15 +297,91 @@ func (b *builder) buildHavingAggregate(aggregate Aggregate) error {
 	if aggregate.distinct {
 		b.writeString("DISTINCT ")
 	}
-	cMeta, ok := b.meta.FieldMap[aggregate.arg]
-	if !ok {
-		return errs.NewInvalidFieldError(aggregate.arg)
+	// 使用 Table 创建的聚合函数，需要使用该表的元数据来校验列
+	if err := b.buildColumn(Column{table: aggregate.table, name: aggregate.arg}); err != nil {
+		return err
 	}
-	b.quote(cMeta.ColumnName)
 	b.writeByte(')')
 	return nil
 }
 
+// buildOrderByList 构造 ORDER BY 子句
+// 字段名优先通过元数据解析，解析不到的时候再尝试作为 SELECT 部分的别名
+func (b *builder) buildOrderByList(orderBys []OrderBy, aliases map[string]struct{}) error {
+	b.writeString(" ORDER BY ")
+	for i, ob := range orderBys {
+		if i > 0 {
+			b.comma()
+		}
+		// 没有指定表的列，和字段名一样处理，这样也可以使用别名
+		if c, ok := ob.expr.(Column); ok && c.table == nil {
+			ob.fields, ob.expr = []string{c.name}, nil
+		}
+		if ob.expr != nil {
+			if err := b.buildOrderByItem(ob, func() error {
+				return b.buildSubExpr(ob.expr)
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		for j, f := range ob.fields {
+			if j > 0 {
+				b.comma()
+			}
+			name := f
+			if err := b.buildOrderByItem(ob, func() error {
+				return b.buildFieldOrAlias(name, aliases)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// buildOrderByItem 构造 ORDER BY 中的一项，target 负责写入排序的表达式
+func (b *builder) buildOrderByItem(ob OrderBy, target func() error) error {
+	emulateNulls := ob.nulls != "" && !b.dialect.SupportNullsOrder()
+	if emulateNulls {
+		// 不支持 NULLS FIRST/LAST 的方言，使用 xxx IS NULL 来模拟，
+		// NULL 的时候 IS NULL 为 1，所以 NULLS FIRST 对应 DESC
+		if err := target(); err != nil {
+			return err
+		}
+		b.writeString(" IS NULL")
+		if ob.nulls == nullsFirst {
+			b.writeString(" DESC")
+		} else {
+			b.writeString(" ASC")
+		}
+		b.comma()
+	}
+	if err := target(); err != nil {
+		return err
+	}
+	b.space()
+	b.writeString(ob.order)
+	if ob.nulls != "" && !emulateNulls {
+		b.writeString(" NULLS ")
+		b.writeString(ob.nulls)
+	}
+	return nil
+}
+
+// buildFieldOrAlias 优先将 name 作为字段名解析，其次作为别名
+func (b *builder) buildFieldOrAlias(name string, aliases map[string]struct{}) error {
+	if cMeta, ok := b.meta.FieldMap[name]; ok {
+		b.quote(cMeta.ColumnName)
+		return nil
+	}
+	if _, ok := aliases[name]; ok {
+		b.quote(name)
+		return nil
+	}
+	return errs.NewInvalidFieldError(name)
+}
+
 func (b *builder) buildBinaryExpr(e binaryExpr) error {
 	err := b.buildSubExpr(e.left)
 	if err != nil {
@@ -223,9 +391,32 @@ func (b *builder) buildBinaryExpr(e binaryExpr) error {
 	return b.buildSubExpr(e.right)
 }
 
+// buildRawExpr 原样输出 RawExpr。如果方言的占位符不是 ?，例如 PostgreSQL，
+// 那么会按照顺序将 ? 替换为对应的占位符，因此 RawExpr 中的字符串字面量不能包含 ?
 func (b *builder) buildRawExpr(e RawExpr) {
-	b.writeString(e.raw)
-	b.args = append(b.args, e.args...)
+	if e.allOf != nil {
+		b.buildAllColumnsOf(e.allOf)
+		return
+	}
+	if len(e.args) == 0 || b.dialect.Placeholder(1) == "?" {
+		b.writeString(e.raw)
+		b.args = append(b.args, e.args...)
+		return
+	}
+	raw := e.raw
+	i := 0
+	for ; i < len(e.args); i++ {
+		idx := strings.IndexByte(raw, '?')
+		if idx < 0 {
+			break
+		}
+		b.writeString(raw[:idx])
+		b.parameter(e.args[i])
+		raw = raw[idx+1:]
+	}
+	b.writeString(raw)
+	// 占位符比参数少的时候，保持原本的行为，剩下的参数依旧传递给驱动
+	b.args = append(b.args, e.args[i:]...)
 }
 
 func (b *builder) buildSubExpr(subExpr Expr) error {
@@ -257,9 +448,7 @@ func (b *builder) buildIns(is values) error {
 			b.writeByte(',')
 		}
 
-		b.args = append(b.args, inVal)
-		b.writeByte('?')
-
+		b.parameter(inVal)
 	}
 	b.writeByte(')')
 	return nil
@@ -311,9 +500,22 @@ func (b *builder) buildColumn(c Column) error {
 	return nil
 }
 
+func (b *builder) buildAllColumnsOf(table TableReference) {
+	if alias := table.getAlias(); alias != "" {
+		b.quote(alias)
+		b.point()
+	}
+	b.writeByte('*')
+}
+
 // buildSubquery 構建子查詢 SQL，
 // useAlias 決定是否顯示別名，即使有別名
 func (b *builder) buildSubquery(sub Subquery, useAlias bool) error {
+	if setter, ok := sub.q.(argOffsetSetter); ok {
+		setter.setArgOffset(b.argOffset + len(b.args))
+		// 子查询单独使用的时候，参数依旧从 1 开始编号
+		defer setter.setArgOffset(0)
+	}
 	q, err := sub.q.Build()
 	if err != nil {
 		return err
@@ -339,3 +541,97 @@ func (b *builder) addArgs(args ...any) {
 	}
 	b.args = append(b.args, args...)
 }
+
+// buildTableReference 构造 UPDATE 和 DELETE 中的表，不考虑 CTE
+func (b *builder) buildTableReference(table TableReference) error {
+	switch t := table.(type) {
+	case nil:
+		b.quote(b.meta.TableName)
+	case Table:
+		m, err := b.metaRegistry.Get(t.entity)
+		if err != nil {
+			return err
+		}
+		b.quote(m.TableName)
+		if t.alias != "" {
+			b.writeString(" AS ")
+			b.quote(t.alias)
+		}
+	case Join:
+		return b.buildJoin(t, b.buildTableReference)
+	case Subquery:
+		return b.buildSubquery(t, true)
+	default:
+		return errs.NewUnsupportedTableReferenceError(table)
+	}
+	return nil
+}
+
+// buildJoin 构造 JOIN，buildTable 负责构造 JOIN 两边的表
+func (b *builder) buildJoin(t Join, buildTable func(table TableReference) error) error {
+	if !b.dialect.SupportJoin(t.typ) {
+		return errs.NewUnsupportedClauseError(b.dialect.Name(), t.typ)
+	}
+	b.writeByte('(')
+	if err := buildTable(t.left); err != nil {
+		return err
+	}
+	b.space()
+	b.writeString(t.typ)
+	b.space()
+	if err := buildTable(t.right); err != nil {
+		return err
+	}
+	if len(t.using) > 0 {
+		if err := b.buildUsing(t); err != nil {
+			return err
+		}
+	}
+	if len(t.on) > 0 {
+		b.writeString(" ON ")
+		if err := b.buildPredicates(t.on); err != nil {
+			return err
+		}
+	}
+	b.writeByte(')')
+	return nil
+}
+
+// buildUsing USING 中的列必须同时存在于 JOIN 的两边，并且列名一致，
+// 所以 JOIN 两边是 Table 的时候，使用各自的元数据来校验
+func (b *builder) buildUsing(t Join) error {
+	metas := make([]*model.TableMeta, 0, 2)
+	for _, side := range []TableReference{t.left, t.right} {
+		if tbl, ok := side.(Table); ok {
+			m, err := b.metaRegistry.Get(tbl.entity)
+			if err != nil {
+				return err
+			}
+			metas = append(metas, m)
+		}
+	}
+	if len(metas) == 0 {
+		metas = append(metas, b.meta)
+	}
+	b.writeString(" USING (")
+	for i, col := range t.using {
+		if i > 0 {
+			b.comma()
+		}
+		var cMeta *model.ColumnMeta
+		for _, m := range metas {
+			fd, ok := m.FieldMap[col]
+			if !ok {
+				return errs.NewInvalidFieldError(col)
+			}
+			// USING 要求两边的列名相同，否则生成的 SQL 至少有一边是错的
+			if cMeta != nil && cMeta.ColumnName != fd.ColumnName {
+				return errs.NewUsingColumnConflictError(col)
+			}
+			cMeta = fd
+		}
+		b.quote(cMeta.ColumnName)
+	}
+	b.writeByte(')')
+	return nil
+}
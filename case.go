@@ -0,0 +1,172 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import "github.com/ecodeclub/eorm/internal/errs"
+
+// CaseBuilder 用于构造 CASE WHEN ... THEN ... ELSE ... END 表达式
+type CaseBuilder struct {
+	whens []caseWhen
+	els   Expr
+}
+
+type caseWhen struct {
+	when Predicate
+	then Expr
+}
+
+// Case 开始构造一个 CASE 表达式
+func Case() *CaseBuilder {
+	return &CaseBuilder{}
+}
+
+// When 即 WHEN pred THEN val，val 可以是普通的值，也可以是 Expr，例如 C("Age").Add(1)
+func (c *CaseBuilder) When(pred Predicate, val any) *CaseBuilder {
+	c.whens = append(c.whens, caseWhen{when: pred, then: valueOf(val)})
+	return c
+}
+
+// Else 即 ELSE val，不调用的时候，不满足所有条件的结果是 NULL
+func (c *CaseBuilder) Else(val any) *CaseBuilder {
+	c.els = valueOf(val)
+	return c
+}
+
+// End 结束构造，返回的 CaseExpr 不会受到 CaseBuilder 后续修改的影响
+func (c *CaseBuilder) End() CaseExpr {
+	whens := make([]caseWhen, len(c.whens))
+	copy(whens, c.whens)
+	return CaseExpr{
+		whens: whens,
+		els:   c.els,
+	}
+}
+
+// CaseExpr 代表 CASE 表达式，既可以用在 SELECT 部分，也可以用在 WHERE 和 SET 部分
+type CaseExpr struct {
+	whens []caseWhen
+	els   Expr
+	alias string
+}
+
+// As 指定别名，只在 SELECT 部分生效
+func (c CaseExpr) As(alias string) Selectable {
+	c.alias = alias
+	return c
+}
+
+// ASC 用于 ORDER BY，例如 ORDER BY CASE WHEN `age`<? THEN ? ELSE ? END ASC
+func (c CaseExpr) ASC() OrderBy {
+	return OrderBy{expr: c, order: "ASC"}
+}
+
+// DESC 用于 ORDER BY，例如 ORDER BY CASE WHEN `age`<? THEN ? ELSE ? END DESC
+func (c CaseExpr) DESC() OrderBy {
+	return OrderBy{expr: c, order: "DESC"}
+}
+
+// EQ =
+func (c CaseExpr) EQ(val any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opEQ,
+		right: valueOf(val),
+	}
+}
+
+// NEQ !=
+func (c CaseExpr) NEQ(val any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opNEQ,
+		right: valueOf(val),
+	}
+}
+
+// LT <
+func (c CaseExpr) LT(val any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLT,
+		right: valueOf(val),
+	}
+}
+
+// LTEQ <=
+func (c CaseExpr) LTEQ(val any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLTEQ,
+		right: valueOf(val),
+	}
+}
+
+// GT >
+func (c CaseExpr) GT(val any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opGT,
+		right: valueOf(val),
+	}
+}
+
+// GTEQ >=
+func (c CaseExpr) GTEQ(val any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opGTEQ,
+		right: valueOf(val),
+	}
+}
+
+// Like LIKE
+func (c CaseExpr) Like(val any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLike,
+		right: valueOf(val),
+	}
+}
+
+func (CaseExpr) expr() (string, error) {
+	return "", nil
+}
+
+func (CaseExpr) selected() {}
+
+func (b *builder) buildCase(c CaseExpr) error {
+	if len(c.whens) == 0 {
+		return errs.ErrCaseWithoutWhen
+	}
+	b.writeString("CASE")
+	for _, w := range c.whens {
+		b.writeString(" WHEN ")
+		if err := b.buildExpr(w.when); err != nil {
+			return err
+		}
+		b.writeString(" THEN ")
+		if err := b.buildSubExpr(w.then); err != nil {
+			return err
+		}
+	}
+	if c.els != nil {
+		b.writeString(" ELSE ")
+		if err := b.buildSubExpr(c.els); err != nil {
+			return err
+		}
+	}
+	b.writeString(" END")
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"testing"
+
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCase(t *testing.T) {
+	db := memoryDB()
+	testCases := []CommonTestCase{
+		{
+			name: "select",
+			builder: NewSelector[TestModel](db).Select(C("Id"),
+				Case().When(C("Age").LT(18), "minor").When(C("Age").GTEQ(60), "senior").Else("adult").End().As("level")),
+			wantSql:  "SELECT `id`,CASE WHEN `age`<? THEN ? WHEN `age`>=? THEN ? ELSE ? END AS `level` FROM `test_model`;",
+			wantArgs: []interface{}{18, "minor", 60, "senior", "adult"},
+		},
+		{
+			name:     "without else and alias",
+			builder:  NewSelector[TestModel](db).Select(Case().When(C("Age").EQ(1), C("FirstName")).End()),
+			wantSql:  "SELECT CASE WHEN `age`=? THEN `first_name` END FROM `test_model`;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "where",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Where(C("Age").GT(Case().When(C("FirstName").EQ("Tom"), 18).Else(20).End())),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `age`>CASE WHEN `first_name`=? THEN ? ELSE ? END;",
+			wantArgs: []interface{}{"Tom", 18, 20},
+		},
+		{
+			name: "compound predicate and math",
+			builder: NewSelector[TestModel](db).Select(
+				Case().When(C("Age").GT(18).And(C("Id").LT(10)), C("Age").Add(1)).Else(C("Age")).End()),
+			wantSql:  "SELECT CASE WHEN (`age`>?) AND (`id`<?) THEN (`age`+?) ELSE `age` END FROM `test_model`;",
+			wantArgs: []interface{}{18, 10, 1},
+		},
+		{
+			name: "update",
+			builder: NewUpdater[TestModel](db).Update(&TestModel{}).
+				Set(Assign("Age", Case().When(C("Age").LT(18), C("Age").Add(1)).Else(C("Age")).End())).
+				Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `test_model` SET `age`=CASE WHEN `age`<? THEN (`age`+?) ELSE `age` END WHERE `id`=?;",
+			wantArgs: []interface{}{18, 1, 1},
+		},
+		{
+			name: "order by",
+			builder: NewSelector[TestModel](db).Select(C("Id")).Where(C("Id").GT(1)).
+				OrderBy(Case().When(C("FirstName").EQ("Tom"), 1).Else(0).End().DESC(), ASC("Id")),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `id`>? ORDER BY CASE WHEN `first_name`=? THEN ? ELSE ? END DESC,`id` ASC;",
+			wantArgs: []interface{}{1, "Tom", 1, 0},
+		},
+		{
+			name: "compare in where",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Where(Case().When(C("Age").LT(18), "minor").Else("adult").End().EQ("adult"),
+					Case().When(C("FirstName").IsNull(), 0).Else(C("Age")).End().GTEQ(C("Id"))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (CASE WHEN `age`<? THEN ? ELSE ? END=?) AND (CASE WHEN `first_name` IS NULL THEN ? ELSE `age` END>=`id`);",
+			wantArgs: []interface{}{18, "minor", "adult", "adult", 0},
+		},
+		{
+			name: "compare operators",
+			builder: func() QueryBuilder {
+				c := Case().When(C("Age").LT(18), 1).Else(2).End()
+				return NewSelector[TestModel](db).Select(C("Id")).
+					Where(c.NEQ(1).Or(c.LT(2)).Or(c.LTEQ(2)).Or(c.GT(1)).Or(c.Like("1%")))
+			}(),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE ((((CASE WHEN `age`<? THEN ? ELSE ? END!=?) OR (CASE WHEN `age`<? THEN ? ELSE ? END<?)) OR (CASE WHEN `age`<? THEN ? ELSE ? END<=?)) OR (CASE WHEN `age`<? THEN ? ELSE ? END>?)) OR (CASE WHEN `age`<? THEN ? ELSE ? END LIKE ?);",
+			wantArgs: []interface{}{18, 1, 2, 1, 18, 1, 2, 2, 18, 1, 2, 2, 18, 1, 2, 1, 18, 1, 2, "1%"},
+		},
+		{
+			name:    "without when",
+			builder: NewSelector[TestModel](db).Select(Case().Else(1).End()),
+			wantErr: errs.ErrCaseWithoutWhen,
+		},
+		{
+			name:    "invalid field",
+			builder: NewSelector[TestModel](db).Select(Case().When(C("Invalid").EQ(1), 1).End()),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestCaseBuilder_End(t *testing.T) {
+	cb := Case().When(C("Age").LT(18), "minor")
+	c := cb.End()
+	cb.When(C("Age").GTEQ(60), "senior")
+	assert.Len(t, c.whens, 1)
+	assert.Len(t, cb.End().whens, 2)
+}
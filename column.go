@@ -113,6 +113,49 @@ func (c Column) NotLike(val interface{}) Predicate {
 	}
 }
 
+// IsNull -> IS NULL
+func (c Column) IsNull() Predicate {
+	return Predicate{
+		left: c,
+		op:   opIsNull,
+	}
+}
+
+// NotNull -> IS NOT NULL
+func (c Column) NotNull() Predicate {
+	return Predicate{
+		left: c,
+		op:   opNotNull,
+	}
+}
+
+// Between -> BETWEEN start AND end，包含两端
+func (c Column) Between(start, end any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opBetween,
+		right: betweenRange(start, end),
+	}
+}
+
+// NotBetween -> NOT BETWEEN start AND end
+func (c Column) NotBetween(start, end any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opNotBetween,
+		right: betweenRange(start, end),
+	}
+}
+
+// betweenRange 即 BETWEEN 右边的 start AND end 部分
+func betweenRange(start, end any) binaryExpr {
+	return binaryExpr{
+		left:  valueOf(start),
+		op:    opAnd,
+		right: valueOf(end),
+	}
+}
+
 // Add generate an additive expression
 func (c Column) Add(val interface{}) MathExpr {
 	return MathExpr{
@@ -131,6 +174,43 @@ func (c Column) Multi(val interface{}) MathExpr {
 	}
 }
 
+// ASC 用于 ORDER BY，使用 Table.C 的时候会带上表的别名
+func (c Column) ASC() OrderBy {
+	return OrderBy{expr: c, order: "ASC"}
+}
+
+// DESC 用于 ORDER BY，使用 Table.C 的时候会带上表的别名
+func (c Column) DESC() OrderBy {
+	return OrderBy{expr: c, order: "DESC"}
+}
+
+// Sub generate a subtraction expression
+func (c Column) Sub(val interface{}) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opMinus,
+		right: valueOf(val),
+	}
+}
+
+// Div generate a division expression
+func (c Column) Div(val interface{}) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
+// Mod generate a modulo expression
+func (c Column) Mod(val interface{}) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opMod,
+		right: valueOf(val),
+	}
+}
+
 func (Column) assign() {
 	panic("implement me")
 }
@@ -190,12 +270,20 @@ func (c Column) In(data ...any) Predicate {
 }
 
 // NotIn 方法没有元素传入，会被认为是false，被解释成where false这种形式
+// 同样支持一個 Subquery 子查詢
 func (c Column) NotIn(data ...any) Predicate {
 	if len(data) == 0 {
 		return Predicate{
 			op: opFalse,
 		}
 	}
+	if sub, ok := data[0].(Subquery); ok {
+		return Predicate{
+			left:  c,
+			op:    opNotIN,
+			right: sub,
+		}
+	}
 	return Predicate{
 		left: c,
 		op:   opNotIN,
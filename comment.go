@@ -0,0 +1,74 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CommentFunc 根据 context.Context 生成 SQL 注释中的键值对，
+// 例如从 context 中取出 trace_id 和 service，返回空的时候不会添加注释
+type CommentFunc func(ctx context.Context) map[string]string
+
+// commenter 为每一条语句加上注释，便于 DBA 通过慢查询日志定位到具体的请求
+type commenter struct {
+	fn CommentFunc
+	// trailing 为 true 的时候注释位于语句的末尾，否则位于语句的开头
+	trailing bool
+}
+
+// comment 返回加上了注释的查询
+// 注释的格式和 SQL commenter 保持一致：键值对按照键排序，并且经过 URL 编码，
+// 这样值里面即便出现了 */ 也不会破坏语句
+func (c commenter) comment(ctx context.Context, q Query) Query {
+	if c.fn == nil {
+		return q
+	}
+	kvs := c.fn(ctx)
+	if len(kvs) == 0 {
+		return q
+	}
+	keys := make([]string, 0, len(kvs))
+	for k := range kvs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString("/* ")
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(url.QueryEscape(k))
+		sb.WriteString("='")
+		sb.WriteString(url.QueryEscape(kvs[k]))
+		sb.WriteByte('\'')
+	}
+	sb.WriteString(" */")
+	if !c.trailing {
+		q.SQL = sb.String() + " " + q.SQL
+		return q
+	}
+	// 注释需要放在分号之前
+	sql, end := q.SQL, ""
+	if strings.HasSuffix(sql, ";") {
+		sql, end = sql[:len(sql)-1], ";"
+	}
+	q.SQL = sql + " " + sb.String() + end
+	return q
+}
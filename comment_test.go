@@ -0,0 +1,151 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ecodeclub/eorm/internal/datasource"
+	"github.com/ecodeclub/eorm/internal/datasource/masterslave"
+	"github.com/ecodeclub/eorm/internal/datasource/masterslave/slaves/roundrobin"
+	"github.com/ecodeclub/eorm/internal/datasource/shardingsource"
+	"github.com/ecodeclub/eorm/internal/datasource/single"
+	"github.com/ecodeclub/eorm/internal/model"
+	"github.com/ecodeclub/eorm/internal/sharding/hash"
+	"github.com/ecodeclub/eorm/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type traceKey struct{}
+
+func traceComment(ctx context.Context) map[string]string {
+	traceId, ok := ctx.Value(traceKey{}).(string)
+	if !ok {
+		return nil
+	}
+	return map[string]string{"trace_id": traceId, "service": "order"}
+}
+
+func TestCommenter_comment(t *testing.T) {
+	ctx := context.WithValue(context.Background(), traceKey{}, "123")
+	testCases := []struct {
+		name      string
+		commenter commenter
+		ctx       context.Context
+		wantSQL   string
+	}{
+		{
+			name:    "no comment func",
+			ctx:     ctx,
+			wantSQL: "SELECT * FROM `order`;",
+		},
+		{
+			name:      "empty comment",
+			commenter: commenter{fn: traceComment},
+			ctx:       context.Background(),
+			wantSQL:   "SELECT * FROM `order`;",
+		},
+		{
+			name:      "leading",
+			commenter: commenter{fn: traceComment},
+			ctx:       ctx,
+			wantSQL:   "/* service='order',trace_id='123' */ SELECT * FROM `order`;",
+		},
+		{
+			name:      "trailing",
+			commenter: commenter{fn: traceComment, trailing: true},
+			ctx:       ctx,
+			wantSQL:   "SELECT * FROM `order` /* service='order',trace_id='123' */;",
+		},
+		{
+			name: "escape",
+			commenter: commenter{fn: func(ctx context.Context) map[string]string {
+				return map[string]string{"route": "/order */ DROP 'x'"}
+			}},
+			ctx:     ctx,
+			wantSQL: "/* route='%2Forder+%2A%2F+DROP+%27x%27' */ SELECT * FROM `order`;",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := tc.commenter.comment(tc.ctx, Query{SQL: "SELECT * FROM `order`;", Args: []any{1}})
+			assert.Equal(t, tc.wantSQL, q.SQL)
+			assert.Equal(t, []any{1}, q.Args)
+		})
+	}
+}
+
+func TestDBWithComment(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := OpenDS("mysql", single.NewDB(mockDB), DBWithComment(traceComment))
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), traceKey{}, "123")
+
+	mock.ExpectQuery("/* service='order',trace_id='123' */ SELECT `id` FROM `test_model` WHERE `id`=? LIMIT ?;").
+		WithArgs(1, 1).
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow(1))
+	res, err := NewSelector[TestModel](db).Select(C("Id")).Where(C("Id").EQ(1)).Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 1}, res)
+
+	mock.ExpectExec("/* service='order',trace_id='123' */ DELETE FROM `test_model` WHERE `id`=?;").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	affected, err := NewDeleter[TestModel](db).Where(C("Id").EQ(1)).Exec(ctx).RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBWithTrailingComment_Sharding(t *testing.T) {
+	r := model.NewMetaRegistry()
+	_, err := r.Register(&test.OrderDetail{},
+		model.WithTableShardingAlgorithm(&hash.Hash{
+			ShardingKey:  "OrderId",
+			DBPattern:    &hash.Pattern{Name: "order_detail_db_%d", Base: 2},
+			TablePattern: &hash.Pattern{Name: "order_detail_tab_%d", Base: 3},
+			DsPattern:    &hash.Pattern{Name: "0.db.slave.company.com:3306", NotSharding: true},
+		}))
+	require.NoError(t, err)
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	rbSlaves, err := roundrobin.NewSlaves(mockDB)
+	require.NoError(t, err)
+	masterSlaveDB := masterslave.NewMasterSlavesDB(
+		mockDB, masterslave.MasterSlavesWithSlaves(newMockSlaveNameGet(rbSlaves)))
+	m := map[string]datasource.DataSource{
+		"0.db.slave.company.com:3306": masterSlaveDB,
+	}
+	shardingDB, err := OpenDS("mysql", shardingsource.NewShardingDataSource(m),
+		DBWithMetaRegistry(r), DBWithTrailingComment(traceComment))
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), traceKey{}, "123")
+
+	mock.ExpectQuery("SELECT `using_col1` FROM `order_detail_db_1`.`order_detail_tab_0` WHERE `order_id`=? LIMIT ? /* service='order',trace_id='123' */;").
+		WithArgs(123, 1).
+		WillReturnRows(mock.NewRows([]string{"using_col1"}).AddRow("LeBron"))
+	res, err := NewShardingSelector[test.OrderDetail](shardingDB).Select(C("UsingCol1")).
+		Where(C("OrderId").EQ(123)).Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &test.OrderDetail{UsingCol1: "LeBron"}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
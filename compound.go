@@ -0,0 +1,206 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+
+	"github.com/ecodeclub/eorm/internal/dialect"
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/valyala/bytebufferpool"
+)
+
+var _ QueryBuilder = &CompoundSelector[any]{}
+
+// CompoundSelector 代表使用 UNION, UNION ALL, INTERSECT 或者 EXCEPT
+// 将多个 Selector 组合在一起的查询
+type CompoundSelector[T any] struct {
+	Session
+	builder
+	first   *Selector[T]
+	parts   []compoundPart[T]
+	orderBy []OrderBy
+	limit   int
+	offset  int
+}
+
+type compoundPart[T any] struct {
+	op string
+	s  *Selector[T]
+}
+
+func newCompoundSelector[T any](first *Selector[T], op string, next *Selector[T]) *CompoundSelector[T] {
+	return &CompoundSelector[T]{
+		Session: first.Session,
+		builder: builder{
+			core:   first.core,
+			buffer: bytebufferpool.Get(),
+		},
+		first: first,
+		parts: []compoundPart[T]{{op: op, s: next}},
+	}
+}
+
+// Union 即 UNION，会去除重复的行
+func (s *Selector[T]) Union(other *Selector[T]) *CompoundSelector[T] {
+	return newCompoundSelector[T](s, "UNION", other)
+}
+
+// UnionAll 即 UNION ALL，保留重复的行
+func (s *Selector[T]) UnionAll(other *Selector[T]) *CompoundSelector[T] {
+	return newCompoundSelector[T](s, "UNION ALL", other)
+}
+
+// Intersect 即 INTERSECT，MySQL 从 8.0.31 开始支持
+func (s *Selector[T]) Intersect(other *Selector[T]) *CompoundSelector[T] {
+	return newCompoundSelector[T](s, "INTERSECT", other)
+}
+
+// Except 即 EXCEPT，MySQL 从 8.0.31 开始支持
+func (s *Selector[T]) Except(other *Selector[T]) *CompoundSelector[T] {
+	return newCompoundSelector[T](s, "EXCEPT", other)
+}
+
+// Union 即 UNION，会去除重复的行
+func (c *CompoundSelector[T]) Union(other *Selector[T]) *CompoundSelector[T] {
+	c.parts = append(c.parts, compoundPart[T]{op: "UNION", s: other})
+	return c
+}
+
+// UnionAll 即 UNION ALL，保留重复的行
+func (c *CompoundSelector[T]) UnionAll(other *Selector[T]) *CompoundSelector[T] {
+	c.parts = append(c.parts, compoundPart[T]{op: "UNION ALL", s: other})
+	return c
+}
+
+// Intersect 即 INTERSECT
+func (c *CompoundSelector[T]) Intersect(other *Selector[T]) *CompoundSelector[T] {
+	c.parts = append(c.parts, compoundPart[T]{op: "INTERSECT", s: other})
+	return c
+}
+
+// Except 即 EXCEPT
+func (c *CompoundSelector[T]) Except(other *Selector[T]) *CompoundSelector[T] {
+	c.parts = append(c.parts, compoundPart[T]{op: "EXCEPT", s: other})
+	return c
+}
+
+// OrderBy 作用于整个组合查询的结果
+func (c *CompoundSelector[T]) OrderBy(orderBys ...OrderBy) *CompoundSelector[T] {
+	c.orderBy = orderBys
+	return c
+}
+
+// Limit 作用于整个组合查询的结果
+func (c *CompoundSelector[T]) Limit(limit int) *CompoundSelector[T] {
+	c.limit = limit
+	return c
+}
+
+// Offset 作用于整个组合查询的结果
+func (c *CompoundSelector[T]) Offset(offset int) *CompoundSelector[T] {
+	c.offset = offset
+	return c
+}
+
+// Build 构造组合查询，每一个 Selector 的列数都必须和第一个 Selector 保持一致
+func (c *CompoundSelector[T]) Build() (Query, error) {
+	// 和 Selector 一样，作为子查询或者 CTE 的时候 Build 可能会被调用多次
+	if c.buffer == nil {
+		c.buffer = bytebufferpool.Get()
+	}
+	c.args = nil
+	defer func() {
+		bytebufferpool.Put(c.buffer)
+		c.buffer = nil
+	}()
+	var err error
+	c.meta, err = c.metaRegistry.Get(new(T))
+	if err != nil {
+		return EmptyQuery, err
+	}
+	if err = c.buildPart(c.first); err != nil {
+		return EmptyQuery, err
+	}
+	cnt := c.first.selectedColumnCount()
+	for _, p := range c.parts {
+		c.space()
+		c.writeString(p.op)
+		c.space()
+		if err = c.buildPart(p.s); err != nil {
+			return EmptyQuery, err
+		}
+		if actual := p.s.selectedColumnCount(); cnt >= 0 && actual >= 0 && actual != cnt {
+			return EmptyQuery, errs.NewColumnCountMismatchError(cnt, actual)
+		}
+	}
+	if len(c.orderBy) > 0 {
+		if err = c.buildOrderBy(); err != nil {
+			return EmptyQuery, err
+		}
+	}
+	c.buildLimitOffset(c.limit, c.offset)
+	c.end()
+	return Query{SQL: c.buffer.String(), Args: c.args}, nil
+}
+
+// buildPart 构造其中一个 SELECT，不带括号，因为 SQLite 不支持，
+// 所以其中的 SELECT 也不能有自己的 ORDER BY、LIMIT、OFFSET 和行锁
+func (c *CompoundSelector[T]) buildPart(s *Selector[T]) error {
+	if len(s.orderBy) > 0 || s.limit > 0 || s.offset > 0 || s.lock.Strength != dialect.LockNone {
+		return errs.ErrCompoundPartOrderLimit
+	}
+	s.setArgOffset(c.argOffset + len(c.args))
+	defer s.setArgOffset(0)
+	q, err := s.Build()
+	if err != nil {
+		return err
+	}
+	c.writeString(q.SQL[:len(q.SQL)-1])
+	if len(q.Args) > 0 {
+		c.addArgs(q.Args...)
+	}
+	return nil
+}
+
+// buildOrderBy 组合查询的 ORDER BY 只能使用结果集中的列，
+// 所以可以使用第一个 Selector 中的别名
+func (c *CompoundSelector[T]) buildOrderBy() error {
+	return c.buildOrderByList(c.orderBy, c.first.selectedAliases())
+}
+
+// selectedColumnCount 组合查询的列数就是第一个 Selector 的列数
+func (c *CompoundSelector[T]) selectedColumnCount() int {
+	return c.first.selectedColumnCount()
+}
+
+// AsSubquery 将整个组合查询作为子查询，也可以作为 CTE 使用
+func (c *CompoundSelector[T]) AsSubquery(alias string) Subquery {
+	return Subquery{
+		entity:  TableOf(new(T), alias),
+		q:       c,
+		alias:   alias,
+		columns: c.first.columns,
+	}
+}
+
+// GetMulti 执行组合查询，返回所有的结果
+func (c *CompoundSelector[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	query, err := c.Build()
+	if err != nil {
+		return nil, err
+	}
+	return newQuerier[T](c.Session, query, c.meta, SELECT).GetMulti(ctx)
+}
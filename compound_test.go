@@ -0,0 +1,183 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ecodeclub/eorm/internal/datasource/single"
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompoundSelector_Build(t *testing.T) {
+	db := memoryDB()
+	pgDB := postgresDB(t)
+	testCases := []CommonTestCase{
+		{
+			name: "union",
+			builder: NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).
+				Union(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").LT(10))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `age`>? UNION SELECT `id` FROM `test_model` WHERE `age`<?;",
+			wantArgs: []interface{}{18, 10},
+		},
+		{
+			name: "union all, intersect and except",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				UnionAll(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").EQ(1))).
+				Intersect(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").EQ(2))).
+				Except(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").EQ(3))),
+			wantSql:  "SELECT `id` FROM `test_model` UNION ALL SELECT `id` FROM `test_model` WHERE `age`=? INTERSECT SELECT `id` FROM `test_model` WHERE `age`=? EXCEPT SELECT `id` FROM `test_model` WHERE `age`=?;",
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name: "order by and limit",
+			builder: NewSelector[TestModel](db).Where(C("Age").GT(18)).
+				Union(NewSelector[TestModel](db).Where(C("Age").LT(10))).
+				OrderBy(DESC("Age")).Limit(10).Offset(5),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>? UNION SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`<? ORDER BY `age` DESC LIMIT ? OFFSET ?;",
+			wantArgs: []interface{}{18, 10, 10, 5},
+		},
+		{
+			name: "postgres",
+			builder: NewSelector[TestModel](pgDB).Select(C("Id")).Where(C("Age").GT(18)).
+				Union(NewSelector[TestModel](pgDB).Select(C("Id")).Where(C("Age").LT(10))).Limit(3),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "age">$1 UNION SELECT "id" FROM "test_model" WHERE "age"<$2 LIMIT $3;`,
+			wantArgs: []interface{}{18, 10, 3},
+		},
+		{
+			name: "subquery",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).
+					Union(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").LT(10))).
+					AsSubquery("sub")
+				return NewSelector[TestModel](db).Where(C("Id").In(sub), C("FirstName").EQ("Tom"))
+			}(),
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE (`id` IN (SELECT `id` FROM `test_model` WHERE `age`>? UNION SELECT `id` FROM `test_model` WHERE `age`<?)) AND (`first_name`=?);",
+			wantArgs: []interface{}{18, 10, "Tom"},
+		},
+		{
+			name: "recursive cte",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				tree := CTEOf(&TestModel{}, "tree")
+				cte := NewSelector[TestModel](db).Select(C("Id"), C("Age")).Where(C("Id").EQ(1)).
+					UnionAll(NewSelector[TestModel](db).Select(t1.C("Id"), t1.C("Age")).
+						From(t1.Join(tree).On(t1.C("Age").EQ(tree.C("Id"))))).
+					AsSubquery("tree")
+				return NewSelector[TestModel](db).WithRecursive(cte).Select(C("Id")).From(cte)
+			}(),
+			wantSql:  "WITH RECURSIVE `tree` AS (SELECT `id`,`age` FROM `test_model` WHERE `id`=? UNION ALL SELECT `t1`.`id`,`t1`.`age` FROM (`test_model` AS `t1` JOIN `tree` ON `t1`.`age`=`tree`.`id`)) SELECT `id` FROM `tree`;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "column count mismatch",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Union(NewSelector[TestModel](db).Select(C("Id"), C("Age"))),
+			wantErr: errs.NewColumnCountMismatchError(1, 2),
+		},
+		{
+			name: "column count mismatch with all columns",
+			builder: NewSelector[TestModel](db).
+				Union(NewSelector[TestModel](db).Select(Columns("Id", "Age"))),
+			wantErr: errs.NewColumnCountMismatchError(4, 2),
+		},
+		{
+			name: "invalid order by",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Union(NewSelector[TestModel](db).Select(C("Id"))).OrderBy(ASC("Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name: "invalid part",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Union(NewSelector[TestModel](db).Select(C("Invalid"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name: "part with order by",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Union(NewSelector[TestModel](db).Select(C("Id")).OrderBy(ASC("Id"))),
+			wantErr: errs.ErrCompoundPartOrderLimit,
+		},
+		{
+			name: "first part with limit",
+			builder: NewSelector[TestModel](db).Select(C("Id")).Limit(1).
+				Union(NewSelector[TestModel](db).Select(C("Id"))),
+			wantErr: errs.ErrCompoundPartOrderLimit,
+		},
+		{
+			name: "part with offset",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Union(NewSelector[TestModel](db).Select(C("Id")).Offset(1)),
+			wantErr: errs.ErrCompoundPartOrderLimit,
+		},
+		{
+			name: "part with lock",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				Union(NewSelector[TestModel](db).Select(C("Id")).ForUpdate()),
+			wantErr: errs.ErrCompoundPartOrderLimit,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestCompoundSelector_BuildTwice(t *testing.T) {
+	db := memoryDB()
+	c := NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).
+		Union(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").LT(10))).Limit(3)
+	wantSql := "SELECT `id` FROM `test_model` WHERE `age`>? UNION SELECT `id` FROM `test_model` WHERE `age`<? LIMIT ?;"
+	for i := 0; i < 2; i++ {
+		q, err := c.Build()
+		require.NoError(t, err)
+		assert.Equal(t, wantSql, q.SQL)
+		assert.Equal(t, []interface{}{18, 10, 3}, q.Args)
+	}
+}
+
+func TestCompoundSelector_GetMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := OpenDS("mysql", single.NewDB(mockDB))
+	require.NoError(t, err)
+
+	rows := mock.NewRows([]string{"id", "first_name"}).AddRow(1, "Tom").AddRow(2, "Jerry")
+	mock.ExpectQuery("SELECT `id`,`first_name` FROM `test_model` WHERE `age`>? UNION ALL SELECT `id`,`first_name` FROM `test_model` WHERE `age`<? ORDER BY `id` ASC;").
+		WithArgs(18, 10).
+		WillReturnRows(rows)
+
+	res, err := NewSelector[TestModel](db).Select(C("Id"), C("FirstName")).Where(C("Age").GT(18)).
+		UnionAll(NewSelector[TestModel](db).Select(C("Id"), C("FirstName")).Where(C("Age").LT(10))).
+		OrderBy(ASC("Id")).GetMulti(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*TestModel{{Id: 1, FirstName: "Tom"}, {Id: 2, FirstName: "Jerry"}}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -17,6 +17,7 @@ package eorm
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/ecodeclub/eorm/internal/dialect"
 	"github.com/ecodeclub/eorm/internal/errs"
@@ -29,6 +30,24 @@ type core struct {
 	dialect      dialect.Dialect
 	valCreator   valuer.PrimitiveCreator
 	ms           []Middleware
+	commenter    commenter
+	// clock 用于自动填充时间，为 nil 的时候使用 time.Now
+	clock func() time.Time
+}
+
+func (c core) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock()
+}
+
+// withComment 在进入 Middleware 之前为查询加上注释，
+// 这样 Middleware，例如 querylog，看到的就是最终执行的语句
+func (c core) withComment(ctx context.Context, qc *QueryContext) *QueryContext {
+	res := *qc
+	res.q = c.commenter.comment(ctx, qc.q)
+	return &res
 }
 
 func getHandler[T any](ctx context.Context, sess Session, c core, qc *QueryContext) *QueryResult {
@@ -68,7 +87,7 @@ func get[T any](ctx context.Context, sess Session, core core, qc *QueryContext)
 	for i := len(ms) - 1; i >= 0; i-- {
 		handler = ms[i](handler)
 	}
-	return handler(ctx, qc)
+	return handler(ctx, core.withComment(ctx, qc))
 }
 
 func getMultiHandler[T any](ctx context.Context, sess Session, c core, qc *QueryContext) *QueryResult {
@@ -110,5 +129,5 @@ func getMulti[T any](ctx context.Context, sess Session, core core, qc *QueryCont
 	for i := len(ms) - 1; i >= 0; i-- {
 		handler = ms[i](handler)
 	}
-	return handler(ctx, qc)
+	return handler(ctx, core.withComment(ctx, qc))
 }
@@ -17,6 +17,7 @@ package eorm
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/ecodeclub/eorm/internal/datasource"
 	"github.com/ecodeclub/eorm/internal/datasource/single"
@@ -41,6 +42,8 @@ type DBOption func(db *DB)
 type DB struct {
 	baseSession
 	ds datasource.DataSource
+	// naming 为 nil 的时候使用 MetaRegistry 自身的命名策略
+	naming NamingStrategy
 }
 
 // DBWithMiddlewares 为 db 配置 Middleware
@@ -50,6 +53,54 @@ func DBWithMiddlewares(ms ...Middleware) DBOption {
 	}
 }
 
+// DBWithComment 为所有的语句加上注释，包括分库分表之后的每一条语句，
+// 注释位于语句的开头，例如 /* service='order',trace_id='123' */ SELECT ...
+func DBWithComment(fn CommentFunc) DBOption {
+	return func(db *DB) {
+		db.commenter = commenter{fn: fn}
+	}
+}
+
+// DBWithTrailingComment 和 DBWithComment 一样，
+// 但是注释位于语句的末尾，例如 SELECT ... /* service='order',trace_id='123' */;
+func DBWithTrailingComment(fn CommentFunc) DBOption {
+	return func(db *DB) {
+		db.commenter = commenter{fn: fn, trailing: true}
+	}
+}
+
+// DBWithClock 指定自动填充 autoCreateTime 和 autoUpdateTime 列所使用的时钟，
+// 默认是 time.Now，一般只在测试的时候使用
+func DBWithClock(clock func() time.Time) DBOption {
+	return func(db *DB) {
+		db.clock = clock
+	}
+}
+
+// NamingStrategy 决定了模型对应的表名和列名
+type NamingStrategy = model.NamingStrategy
+
+// 内置的命名策略
+var (
+	// Underscore 默认的命名策略，每个大写字母都是一个新单词的开始，例如 UserID 对应的是 user_i_d
+	Underscore = model.Underscore
+	// SnakeCase 下划线风格，会识别缩写，例如 UserID 对应的是 user_id
+	SnakeCase = model.SnakeCase
+	// CamelCase 小驼峰风格，例如 UserID 对应的是 userID
+	CamelCase = model.CamelCase
+	// TablePrefix 在另外一个命名策略的基础上给表名加上前缀
+	TablePrefix = model.TablePrefix
+)
+
+// DBWithNamingStrategy 使用 naming 作为命名策略。
+// 命名策略会在所有的 DBOption 执行完毕之后设置到 MetaRegistry 上，所以和 DBWithMetaRegistry 的顺序无关。
+// 注意和 DBWithMetaRegistry 一起使用的时候会修改传入的 MetaRegistry，并且只影响之后注册的模型
+func DBWithNamingStrategy(naming NamingStrategy) DBOption {
+	return func(db *DB) {
+		db.naming = naming
+	}
+}
+
 func DBWithMetaRegistry(r model.MetaRegistry) DBOption {
 	return func(db *DB) {
 		db.metaRegistry = r
@@ -94,6 +145,13 @@ func OpenDS(driver string, ds datasource.DataSource, opts ...DBOption) (*DB, err
 	for _, o := range opts {
 		o(orm)
 	}
+	if orm.naming != nil {
+		r, ok := orm.metaRegistry.(interface{ SetNamingStrategy(naming NamingStrategy) })
+		if !ok {
+			return nil, errs.ErrNamingStrategyUnsupported
+		}
+		r.SetNamingStrategy(orm.naming)
+	}
 	return orm, nil
 }
 
@@ -23,7 +23,10 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/ecodeclub/eorm/internal/datasource/single"
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/ecodeclub/eorm/internal/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ecodeclub/eorm/internal/datasource/masterslave"
 
@@ -54,6 +57,88 @@ func TestDB_BeginTx(t *testing.T) {
 	assert.NotNil(t, tx)
 }
 
+func TestDBWithNamingStrategy(t *testing.T) {
+	type UserInfo struct {
+		UserID   int64
+		NickName string
+	}
+	db, err := Open("sqlite3", "file:test.db?cache=shared&mode=memory",
+		DBWithNamingStrategy(TablePrefix("t_", SnakeCase())))
+	require.NoError(t, err)
+	q, err := NewSelector[UserInfo](db).Where(C("UserID").EQ(1)).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `user_id`,`nick_name` FROM `t_user_info` WHERE `user_id`=?;", q.SQL)
+
+	db, err = Open("sqlite3", "file:test.db?cache=shared&mode=memory",
+		DBWithNamingStrategy(CamelCase()))
+	require.NoError(t, err)
+	q, err = NewSelector[UserInfo](db).Where(C("UserID").EQ(1)).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `userID`,`nickName` FROM `userInfo` WHERE `userID`=?;", q.SQL)
+
+	// 默认的命名策略保持原本的行为
+	db, err = Open("sqlite3", "file:test.db?cache=shared&mode=memory")
+	require.NoError(t, err)
+	q, err = NewSelector[UserInfo](db).Where(C("UserID").EQ(1)).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT `user_i_d`,`nick_name` FROM `user_info` WHERE `user_i_d`=?;", q.SQL)
+}
+
+func TestDBWithNamingStrategy_MetaRegistry(t *testing.T) {
+	type UserInfo struct {
+		UserID   int64
+		NickName string
+	}
+	testCases := []struct {
+		name    string
+		opts    func(r model.MetaRegistry) []DBOption
+		wantSQL string
+		wantErr error
+	}{
+		{
+			name: "naming first",
+			opts: func(r model.MetaRegistry) []DBOption {
+				return []DBOption{DBWithNamingStrategy(SnakeCase()), DBWithMetaRegistry(r)}
+			},
+			wantSQL: "SELECT `user_id`,`nick_name` FROM `user_info` WHERE `user_id`=?;",
+		},
+		{
+			name: "registry first",
+			opts: func(r model.MetaRegistry) []DBOption {
+				return []DBOption{DBWithMetaRegistry(r), DBWithNamingStrategy(SnakeCase())}
+			},
+			wantSQL: "SELECT `user_id`,`nick_name` FROM `user_info` WHERE `user_id`=?;",
+		},
+		{
+			name: "unsupported registry",
+			opts: func(r model.MetaRegistry) []DBOption {
+				return []DBOption{DBWithMetaRegistry(fixedMetaRegistry{MetaRegistry: r}), DBWithNamingStrategy(SnakeCase())}
+			},
+			wantErr: errs.ErrNamingStrategyUnsupported,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := model.NewMetaRegistry()
+			db, err := Open("sqlite3", "file:test.db?cache=shared&mode=memory", tc.opts(r)...)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			// 命名策略设置在传入的 MetaRegistry 上
+			assert.Equal(t, r, db.metaRegistry)
+			q, err := NewSelector[UserInfo](db).Where(C("UserID").EQ(1)).Build()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSQL, q.SQL)
+		})
+	}
+}
+
+// fixedMetaRegistry 不支持设置命名策略
+type fixedMetaRegistry struct {
+	model.MetaRegistry
+}
+
 func ExampleMiddleware() {
 	db, _ := Open("sqlite3", "file:test.db?cache=shared&mode=memory",
 		DBWithMiddlewares(func(next HandleFunc) HandleFunc {
@@ -95,7 +180,7 @@ func ExampleDB_BeginTx() {
 func ExampleOpen() {
 	// case1 without DBOption
 	db, _ := Open("sqlite3", "file:test.db?cache=shared&mode=memory")
-	fmt.Printf("case1 dialect: %s\n", db.dialect.Name)
+	fmt.Printf("case1 dialect: %s\n", db.dialect.Name())
 
 	// Output:
 	// case1 dialect: SQLite
@@ -130,6 +215,18 @@ func memoryDB() *DB {
 	return db
 }
 
+// postgresDB 返回一个使用 PostgreSQL 方言的 DB，只用于测试 SQL 的构造
+func postgresDB(t *testing.T) *DB {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = mockDB.Close()
+	})
+	db, err := OpenDS("postgres", single.NewDB(mockDB))
+	require.NoError(t, err)
+	return db
+}
+
 func memoryDBWithDB(dbName string) *DB {
 	db, err := Open("sqlite3", fmt.Sprintf("file:%s.db?cache=shared&mode=memory", dbName))
 	if err != nil {
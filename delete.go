@@ -17,6 +17,7 @@ package eorm
 import (
 	"context"
 
+	"github.com/ecodeclub/eorm/internal/errs"
 	"github.com/valyala/bytebufferpool"
 )
 
@@ -26,8 +27,16 @@ var _ QueryBuilder = &Deleter[any]{}
 type Deleter[T any] struct {
 	builder
 	Session
-	table interface{}
-	where []Predicate
+	table     interface{}
+	targets   []Table
+	where     []Predicate
+	orderBy   []OrderBy
+	limit     int
+	returning []string
+	// unscoped 为 true 的时候，被软删除的数据也会被删除
+	unscoped bool
+	// hardDelete 为 true 的时候，即便支持软删除，也会物理删除
+	hardDelete bool
 }
 
 // NewDeleter 开始构建一个 DELETE 查询
@@ -44,40 +53,214 @@ func NewDeleter[T any](sess Session) *Deleter[T] {
 // Build returns DELETE query
 func (d *Deleter[T]) Build() (Query, error) {
 	defer bytebufferpool.Put(d.buffer)
-	_, _ = d.buffer.WriteString("DELETE FROM ")
 	var err error
-	if d.table == nil {
+	switch tbl := d.table.(type) {
+	case nil:
 		d.table = new(T)
+		d.meta, err = d.metaRegistry.Get(d.table)
+	case Table:
+		d.meta, err = d.metaRegistry.Get(tbl.entity)
+	case Join:
+		d.meta, err = d.metaRegistry.Get(new(T))
+	case Subquery:
+		return EmptyQuery, errs.NewUnsupportedTableReferenceError(tbl)
+	default:
+		d.meta, err = d.metaRegistry.Get(d.table)
 	}
-	d.meta, err = d.metaRegistry.Get(d.table)
 	if err != nil {
 		return EmptyQuery, err
 	}
 
-	d.quote(d.meta.TableName)
-	if len(d.where) > 0 {
+	soft := d.meta.SoftDeleteColumn != nil && !d.hardDelete
+	var target TableReference
+	switch tbl := d.table.(type) {
+	case Table:
+		target = tbl
+		if soft {
+			err = d.buildSoftDelete(tbl)
+			break
+		}
+		d.writeString("DELETE FROM ")
+		err = d.buildTableReference(tbl)
+	case Join:
+		if soft {
+			return EmptyQuery, errs.ErrSoftDeleteJoin
+		}
+		err = d.buildJoinTarget(tbl)
+	default:
+		if soft {
+			err = d.buildSoftDelete(nil)
+			break
+		}
+		d.writeString("DELETE FROM ")
+		d.quote(d.meta.TableName)
+	}
+	if err != nil {
+		return EmptyQuery, err
+	}
+
+	where := d.where
+	if soft && !d.unscoped {
+		if _, where, err = d.withNotDeleted(target, where); err != nil {
+			return EmptyQuery, err
+		}
+	}
+	if len(where) > 0 {
 		d.writeString(" WHERE ")
-		err = d.buildPredicates(d.where)
+		err = d.buildPredicates(where)
 		if err != nil {
 			return EmptyQuery, err
 		}
 	}
+	if len(d.orderBy) > 0 || d.limit > 0 {
+		if err = d.buildOrderLimit(); err != nil {
+			return EmptyQuery, err
+		}
+	}
+	if len(d.returning) > 0 {
+		if err = d.buildReturning(d.returning); err != nil {
+			return EmptyQuery, err
+		}
+	}
 	d.end()
 	return Query{SQL: d.buffer.String(), Args: d.args}, nil
 }
 
+// buildSoftDelete 软删除实际上是 UPDATE，例如 UPDATE `user` SET `deleted_at`=?
+// SET 中的列不带表名或者别名，因为 SQLite 和 PostgreSQL 都不支持
+func (d *Deleter[T]) buildSoftDelete(table TableReference) error {
+	d.writeString("UPDATE ")
+	if table == nil {
+		d.quote(d.meta.TableName)
+	} else if err := d.buildTableReference(table); err != nil {
+		return err
+	}
+	d.writeString(" SET ")
+	cm := d.meta.SoftDeleteColumn
+	return d.buildExpr(binaryExpr(C(cm.FieldName).Assign(cm.DeletedValue(d.now()))))
+}
+
+// buildJoinTarget 构造多表 DELETE 中 WHERE 之前的部分，
+// 例如 DELETE `t1` FROM (`a` AS `t1` JOIN `b` AS `t2` ON ...)
+func (d *Deleter[T]) buildJoinTarget(join Join) error {
+	if !d.dialect.SupportJoinMutation() {
+		return errs.NewUnsupportedClauseError(d.dialect.Name(), "DELETE JOIN")
+	}
+	targets := d.targets
+	if len(targets) == 0 {
+		// 没有指定的时候，删除的是最左边的表
+		left, ok := leftmostTable(join)
+		if !ok {
+			return errs.NewUnsupportedTableReferenceError(join.left)
+		}
+		targets = []Table{left}
+	}
+	d.writeString("DELETE ")
+	for i, t := range targets {
+		if i > 0 {
+			d.comma()
+		}
+		if t.alias != "" {
+			d.quote(t.alias)
+			continue
+		}
+		m, err := d.metaRegistry.Get(t.entity)
+		if err != nil {
+			return err
+		}
+		d.quote(m.TableName)
+	}
+	d.writeString(" FROM ")
+	return d.buildTableReference(join)
+}
+
+func (d *Deleter[T]) buildOrderLimit() error {
+	if !d.dialect.SupportDeleteOrderLimit() {
+		clause := "LIMIT"
+		if len(d.orderBy) > 0 {
+			clause = "ORDER BY"
+		}
+		return errs.NewUnsupportedClauseError(d.dialect.Name(), clause)
+	}
+	// MySQL 的多表 DELETE 不能使用 ORDER BY 和 LIMIT
+	if _, ok := d.table.(Join); ok {
+		return errs.ErrDeleteJoinWithOrderLimit
+	}
+	if len(d.orderBy) > 0 {
+		if err := d.buildOrderByList(d.orderBy, nil); err != nil {
+			return err
+		}
+	}
+	d.buildLimitOffset(d.limit, 0)
+	return nil
+}
+
+// leftmostTable 返回 JOIN 中最左边的表
+func leftmostTable(table TableReference) (Table, bool) {
+	switch t := table.(type) {
+	case Table:
+		return t, true
+	case Join:
+		return leftmostTable(t.left)
+	default:
+		return Table{}, false
+	}
+}
+
 // From accepts model definition
+// 也可以是 TableOf 创建的 Table，或者是 JOIN，
+// 使用 JOIN 的时候可以通过 Targets 来指定删除哪些表中的数据
 func (d *Deleter[T]) From(table interface{}) *Deleter[T] {
 	d.table = table
 	return d
 }
 
+// Targets 指定多表 DELETE 中需要删除数据的表，
+// 没有指定的时候，删除的是 JOIN 中最左边的表
+func (d *Deleter[T]) Targets(tables ...Table) *Deleter[T] {
+	d.targets = tables
+	return d
+}
+
+// OrderBy 指定删除的顺序，一般和 Limit 一起使用来分批删除
+// 并不是所有的方言都支持，例如 PostgreSQL 就不支持
+func (d *Deleter[T]) OrderBy(orderBys ...OrderBy) *Deleter[T] {
+	d.orderBy = orderBys
+	return d
+}
+
+// Limit 指定最多删除多少行
+// 并不是所有的方言都支持，例如 PostgreSQL 就不支持
+func (d *Deleter[T]) Limit(limit int) *Deleter[T] {
+	d.limit = limit
+	return d
+}
+
+// Unscoped 软删除的时候，不再过滤已经被软删除的数据
+func (d *Deleter[T]) Unscoped() *Deleter[T] {
+	d.unscoped = true
+	return d
+}
+
+// HardDelete 即便模型支持软删除，也使用 DELETE 物理删除数据
+func (d *Deleter[T]) HardDelete() *Deleter[T] {
+	d.hardDelete = true
+	return d
+}
+
 // Where accepts predicates
 func (d *Deleter[T]) Where(predicates ...Predicate) *Deleter[T] {
 	d.where = predicates
 	return d
 }
 
+// Returning 指定 RETURNING 子句中返回的字段
+// 并不是所有的方言都支持 RETURNING，例如 MySQL 就不支持
+func (d *Deleter[T]) Returning(fields ...string) *Deleter[T] {
+	d.returning = fields
+	return d
+}
+
 // Exec sql
 func (d *Deleter[T]) Exec(ctx context.Context) Result {
 	query, err := d.Build()
@@ -33,6 +33,8 @@ import (
 
 func TestDeleter_Build(t *testing.T) {
 	db := memoryDB()
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
 	testCases := []CommonTestCase{
 		{
 			name:    "no where",
@@ -56,6 +58,79 @@ func TestDeleter_Build(t *testing.T) {
 			wantSql:  "DELETE FROM `test_combined_model` WHERE `create_time`=?;",
 			wantArgs: []interface{}{uint64(1000)},
 		},
+		{
+			name:     "postgres returning",
+			builder:  NewDeleter[TestModel](postgresDB(t)).Where(C("Id").EQ(16).Or(C("Age").LT(18))).Returning("Id"),
+			wantSql:  `DELETE FROM "test_model" WHERE ("id"=$1) OR ("age"<$2) RETURNING "id";`,
+			wantArgs: []interface{}{16, 18},
+		},
+		{
+			name:    "returning invalid field",
+			builder: NewDeleter[TestModel](postgresDB(t)).Returning("Invalid"),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:     "order by and limit",
+			builder:  NewDeleter[TestModel](mysqlDB).Where(C("Age").LT(18)).OrderBy(ASC("Id")).Limit(1000),
+			wantSql:  "DELETE FROM `test_model` WHERE `age`<? ORDER BY `id` ASC LIMIT ?;",
+			wantArgs: []interface{}{18, 1000},
+		},
+		{
+			name:    "limit unsupported",
+			builder: NewDeleter[TestModel](db).Limit(1000),
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "LIMIT"),
+		},
+		{
+			name:    "order by unsupported",
+			builder: NewDeleter[TestModel](postgresDB(t)).OrderBy(ASC("Id")).Limit(1000),
+			wantErr: errs.NewUnsupportedClauseError("PostgreSQL", "ORDER BY"),
+		},
+		{
+			name:     "table with alias",
+			builder:  NewDeleter[TestModel](db).From(TableOf(&TestModel{}, "t1")).Where(C("Id").EQ(16)),
+			wantSql:  "DELETE FROM `test_model` AS `t1` WHERE `id`=?;",
+			wantArgs: []interface{}{16},
+		},
+		{
+			name: "join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestCombinedModel{}, "t2")
+				return NewDeleter[TestModel](mysqlDB).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Id")))).
+					Where(t2.C("CreateTime").LT(uint64(1000)))
+			}(),
+			wantSql:  "DELETE `t1` FROM (`test_model` AS `t1` JOIN `test_combined_model` AS `t2` ON `t1`.`id`=`t2`.`id`) WHERE `t2`.`create_time`<?;",
+			wantArgs: []interface{}{uint64(1000)},
+		},
+		{
+			name: "join with targets",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestCombinedModel{}, "t2")
+				return NewDeleter[TestModel](mysqlDB).From(t1.LeftJoin(t2).On(t1.C("Id").EQ(t2.C("Id")))).
+					Targets(t1, t2).Where(t1.C("Age").GT(18))
+			}(),
+			wantSql:  "DELETE `t1`,`t2` FROM (`test_model` AS `t1` LEFT JOIN `test_combined_model` AS `t2` ON `t1`.`id`=`t2`.`id`) WHERE `t1`.`age`>?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "join with limit",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewDeleter[TestModel](mysqlDB).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age")))).Limit(10)
+			}(),
+			wantErr: errs.ErrDeleteJoinWithOrderLimit,
+		},
+		{
+			name: "join unsupported",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewDeleter[TestModel](db).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age"))))
+			}(),
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "DELETE JOIN"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -25,6 +25,8 @@ type Expr interface {
 type RawExpr struct {
 	raw  string
 	args []interface{}
+	// allOf 不为 nil 的时候代表 allOf.*，见 Table.AllColumns
+	allOf TableReference
 }
 
 // Raw just take expr alias Expr
@@ -77,10 +79,44 @@ func (m MathExpr) Multi(val interface{}) MathExpr {
 	}
 }
 
+func (m MathExpr) Sub(val interface{}) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opMinus,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Div(val interface{}) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Mod(val interface{}) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opMod,
+		right: valueOf(val),
+	}
+}
+
 func (MathExpr) expr() (string, error) {
 	return "", nil
 }
 
+// ASC 用于 ORDER BY，例如 ORDER BY (`age`+?) ASC
+func (m MathExpr) ASC() OrderBy {
+	return OrderBy{expr: m, order: "ASC"}
+}
+
+// DESC 用于 ORDER BY，例如 ORDER BY (`age`+?) DESC
+func (m MathExpr) DESC() OrderBy {
+	return OrderBy{expr: m, order: "DESC"}
+}
+
 func valueOf(val interface{}) Expr {
 	switch v := val.(type) {
 	case Expr:
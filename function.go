@@ -0,0 +1,194 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import "github.com/ecodeclub/eorm/internal/dialect"
+
+// FuncExpr 代表函数调用，例如 COALESCE(`age`,?)
+// 参数如果是 Expr，例如 C("Age")，那么会按照 Expr 来构造，列名会通过元数据来解析；
+// 否则会被当做参数，例如 Coalesce(C("Age"), 0) 中的 0
+type FuncExpr struct {
+	name  string
+	args  []Expr
+	alias string
+}
+
+// Func 构造任意的函数调用，name 会被原样输出
+// 优先使用 Coalesce 这一类预定义的函数，它们会处理不同方言之间的差异
+func Func(name string, args ...any) FuncExpr {
+	exprs := make([]Expr, 0, len(args))
+	for _, arg := range args {
+		exprs = append(exprs, valueOf(arg))
+	}
+	return FuncExpr{
+		name: name,
+		args: exprs,
+	}
+}
+
+// Coalesce represents COALESCE
+func Coalesce(args ...any) FuncExpr {
+	return Func("COALESCE", args...)
+}
+
+// Concat 拼接字符串，在 SQLite 里面是 ||
+func Concat(args ...any) FuncExpr {
+	return Func(dialect.FuncConcat, args...)
+}
+
+// Lower represents LOWER
+func Lower(arg any) FuncExpr {
+	return Func("LOWER", arg)
+}
+
+// Upper represents UPPER
+func Upper(arg any) FuncExpr {
+	return Func("UPPER", arg)
+}
+
+// Trim represents TRIM
+func Trim(arg any) FuncExpr {
+	return Func("TRIM", arg)
+}
+
+// Length 返回字符数，在 MySQL 里面是 CHAR_LENGTH
+func Length(arg any) FuncExpr {
+	return Func(dialect.FuncLength, arg)
+}
+
+// Substring 截取字符串，pos 从 1 开始，在 SQLite 里面是 SUBSTR
+func Substring(arg any, pos int, length int) FuncExpr {
+	return Func(dialect.FuncSubstring, arg, pos, length)
+}
+
+// Abs represents ABS
+func Abs(arg any) FuncExpr {
+	return Func("ABS", arg)
+}
+
+// Round represents ROUND
+func Round(arg any, decimals int) FuncExpr {
+	return Func("ROUND", arg, decimals)
+}
+
+// Date 取日期部分，在 PostgreSQL 里面是 CAST(xxx AS DATE)
+func Date(arg any) FuncExpr {
+	return Func(dialect.FuncDate, arg)
+}
+
+// Now 当前时间，在 SQLite 里面是 DATETIME('now')
+func Now() FuncExpr {
+	return Func(dialect.FuncNow)
+}
+
+// As 指定别名，只在 SELECT 部分生效
+func (f FuncExpr) As(alias string) Selectable {
+	f.alias = alias
+	return f
+}
+
+// ASC 用于 ORDER BY
+func (f FuncExpr) ASC() OrderBy {
+	return OrderBy{expr: f, order: "ASC"}
+}
+
+// DESC 用于 ORDER BY
+func (f FuncExpr) DESC() OrderBy {
+	return OrderBy{expr: f, order: "DESC"}
+}
+
+// EQ =
+func (f FuncExpr) EQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opEQ,
+		right: valueOf(val),
+	}
+}
+
+// NEQ !=
+func (f FuncExpr) NEQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opNEQ,
+		right: valueOf(val),
+	}
+}
+
+// LT <
+func (f FuncExpr) LT(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opLT,
+		right: valueOf(val),
+	}
+}
+
+// LTEQ <=
+func (f FuncExpr) LTEQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opLTEQ,
+		right: valueOf(val),
+	}
+}
+
+// GT >
+func (f FuncExpr) GT(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opGT,
+		right: valueOf(val),
+	}
+}
+
+// GTEQ >=
+func (f FuncExpr) GTEQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opGTEQ,
+		right: valueOf(val),
+	}
+}
+
+// Like LIKE
+func (f FuncExpr) Like(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opLike,
+		right: valueOf(val),
+	}
+}
+
+func (FuncExpr) expr() (string, error) {
+	return "", nil
+}
+
+func (FuncExpr) selected() {}
+
+func (b *builder) buildFunc(f FuncExpr) error {
+	tpl := b.dialect.Func(f.name)
+	b.writeString(tpl.Prefix)
+	for i, arg := range f.args {
+		if i > 0 {
+			b.writeString(tpl.Sep)
+		}
+		if err := b.buildSubExpr(arg); err != nil {
+			return err
+		}
+	}
+	b.writeString(tpl.Suffix)
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"testing"
+
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncExpr(t *testing.T) {
+	db := memoryDB()
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
+	pgDB := postgresDB(t)
+	testCases := []CommonTestCase{
+		{
+			name:     "coalesce",
+			builder:  NewSelector[TestModel](db).Select(C("Id"), Coalesce(C("LastName"), "N/A").As("last_name")),
+			wantSql:  "SELECT `id`,COALESCE(`last_name`,?) AS `last_name` FROM `test_model`;",
+			wantArgs: []interface{}{"N/A"},
+		},
+		{
+			name:     "concat mysql",
+			builder:  NewSelector[TestModel](mysqlDB).Select(Concat(C("FirstName"), " ", C("LastName")).As("name")),
+			wantSql:  "SELECT CONCAT(`first_name`,?,`last_name`) AS `name` FROM `test_model`;",
+			wantArgs: []interface{}{" "},
+		},
+		{
+			name:     "concat sqlite",
+			builder:  NewSelector[TestModel](db).Select(Concat(C("FirstName"), " ", C("LastName")).As("name")),
+			wantSql:  "SELECT (`first_name`||?||`last_name`) AS `name` FROM `test_model`;",
+			wantArgs: []interface{}{" "},
+		},
+		{
+			name:     "predicate",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(Lower(C("FirstName")).EQ("tom"), Length(C("LastName")).GT(3)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (LOWER(`first_name`)=?) AND (LENGTH(`last_name`)>?);",
+			wantArgs: []interface{}{"tom", 3},
+		},
+		{
+			name:     "length mysql",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(Length(C("LastName")).LTEQ(3)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE CHAR_LENGTH(`last_name`)<=?;",
+			wantArgs: []interface{}{3},
+		},
+		{
+			name: "group by and order by",
+			builder: NewSelector[TestModel](db).Select(Date(C("FirstName")).As("day"), Count("Id")).
+				GroupByExprs(Date(C("FirstName"))).OrderBy(Date(C("FirstName")).DESC()),
+			wantSql: "SELECT DATE(`first_name`) AS `day`,COUNT(`id`) FROM `test_model` GROUP BY DATE(`first_name`) ORDER BY DATE(`first_name`) DESC;",
+		},
+		{
+			name: "group by fields and exprs",
+			builder: NewSelector[TestModel](db).Select(C("Age"), Upper(C("FirstName"))).
+				GroupBy("Age").GroupByExprs(Upper(C("FirstName"))).OrderBy(ASC("Age"), Upper(C("FirstName")).ASC()),
+			wantSql: "SELECT `age`,UPPER(`first_name`) FROM `test_model` GROUP BY `age`,UPPER(`first_name`) ORDER BY `age` ASC,UPPER(`first_name`) ASC;",
+		},
+		{
+			name:     "postgres",
+			builder:  NewSelector[TestModel](pgDB).Select(Date(C("FirstName")), Substring(C("LastName"), 1, 3)).Where(Abs(C("Age")).GTEQ(1)),
+			wantSql:  `SELECT CAST("first_name" AS DATE),SUBSTRING("last_name",$1,$2) FROM "test_model" WHERE ABS("age")>=$3;`,
+			wantArgs: []interface{}{1, 3, 1},
+		},
+		{
+			name:     "sqlite substring and now",
+			builder:  NewSelector[TestModel](db).Select(Substring(C("LastName"), 1, 3), Now()).Where(Trim(C("FirstName")).Like("T%")),
+			wantSql:  "SELECT SUBSTR(`last_name`,?,?),DATETIME('now') FROM `test_model` WHERE TRIM(`first_name`) LIKE ?;",
+			wantArgs: []interface{}{1, 3, "T%"},
+		},
+		{
+			name:     "custom func",
+			builder:  NewSelector[TestModel](mysqlDB).Select(Func("IFNULL", C("Age"), 0), Round(C("Age"), 2)).Where(Now().NEQ(C("Age")), Abs(C("Age")).LT(1)),
+			wantSql:  "SELECT IFNULL(`age`,?),ROUND(`age`,?) FROM `test_model` WHERE (NOW()!=`age`) AND (ABS(`age`)<?);",
+			wantArgs: []interface{}{0, 2, 1},
+		},
+		{
+			name:     "update",
+			builder:  NewUpdater[TestModel](db).Update(&TestModel{}).Set(Assign("FirstName", Upper(C("FirstName")))).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `test_model` SET `first_name`=UPPER(`first_name`) WHERE `id`=?;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "invalid field",
+			builder: NewSelector[TestModel](db).Select(Coalesce(C("Invalid"), 0)),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
@@ -16,10 +16,14 @@ package eorm
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"reflect"
 
+	"github.com/ecodeclub/eorm/internal/dialect"
 	"github.com/ecodeclub/eorm/internal/errs"
 	"github.com/ecodeclub/eorm/internal/model"
+	"github.com/ecodeclub/eorm/internal/rows"
 	"github.com/valyala/bytebufferpool"
 )
 
@@ -31,6 +35,9 @@ type Inserter[T any] struct {
 	inserterBuilder
 	db     Session
 	values []*T
+	// src 是 INSERT SELECT 中的 SELECT 部分
+	src       QueryBuilder
+	batchInTx bool
 }
 
 // NewInserter 开始构建一个 INSERT 查询
@@ -58,14 +65,19 @@ func (i *Inserter[T]) SkipPK() *Inserter[T] {
 func (i *Inserter[T]) Build() (Query, error) {
 	defer bytebufferpool.Put(i.buffer)
 	var err error
-	if len(i.values) == 0 {
+	if len(i.values) == 0 && i.src == nil {
 		return EmptyQuery, errors.New("插入0行")
 	}
-	i.writeString("INSERT INTO ")
-	i.meta, err = i.metaRegistry.Get(i.values[0])
+	if i.mode != dialect.InsertModeDefault && i.upsert != nil {
+		return EmptyQuery, errs.ErrInsertModeWithUpsert
+	}
+	i.meta, err = i.metaRegistry.Get(new(T))
 	if err != nil {
 		return EmptyQuery, err
 	}
+	if err = i.dialect.BuildInsert(dialectWriter{b: &i.builder}, i.mode); err != nil {
+		return EmptyQuery, err
+	}
 	i.quote(i.meta.TableName)
 	i.writeString("(")
 	fields, err := i.buildColumns()
@@ -73,7 +85,31 @@ func (i *Inserter[T]) Build() (Query, error) {
 		return EmptyQuery, err
 	}
 	i.writeString(")")
+	if i.src != nil {
+		if err = i.buildSelect(len(fields)); err != nil {
+			return EmptyQuery, err
+		}
+	} else if err = i.buildValues(fields); err != nil {
+		return EmptyQuery, err
+	}
+	i.dialect.BuildInsertSuffix(dialectWriter{b: &i.builder}, i.mode)
+	if i.upsert != nil {
+		if err = i.buildUpsert(i.upsert); err != nil {
+			return EmptyQuery, err
+		}
+	}
+	if len(i.returning) > 0 {
+		if err = i.buildReturning(i.returning); err != nil {
+			return EmptyQuery, err
+		}
+	}
+	i.end()
+	return Query{SQL: i.buffer.String(), Args: i.args}, nil
+}
+
+func (i *Inserter[T]) buildValues(fields []*model.ColumnMeta) error {
 	i.writeString(" VALUES")
+	now := i.now()
 	for index, val := range i.values {
 		if index > 0 {
 			i.comma()
@@ -83,17 +119,41 @@ func (i *Inserter[T]) Build() (Query, error) {
 		for j, v := range fields {
 			fdVal, err := refVal.Field(v.FieldName)
 			if err != nil {
-				return EmptyQuery, err
+				return err
 			}
-			i.parameter(fdVal.Interface())
+			i.parameter(insertValue(v, fdVal, now))
 			if j != len(fields)-1 {
 				i.comma()
 			}
 		}
 		i.writeString(")")
 	}
-	i.end()
-	return Query{SQL: i.buffer.String(), Args: i.args}, nil
+	return nil
+}
+
+// buildSelect 构造 INSERT SELECT 中的 SELECT 部分
+// cnt 是插入的列数
+func (i *Inserter[T]) buildSelect(cnt int) error {
+	if setter, ok := i.src.(argOffsetSetter); ok {
+		setter.setArgOffset(len(i.args))
+		defer setter.setArgOffset(0)
+	}
+	q, err := i.src.Build()
+	if err != nil {
+		return err
+	}
+	if counter, ok := i.src.(columnCounter); ok {
+		if n := counter.selectedColumnCount(); n >= 0 && n != cnt {
+			return errs.NewColumnCountMismatchError(cnt, n)
+		}
+	}
+	i.space()
+	// 拿掉最後 ';'
+	i.writeString(q.SQL[:len(q.SQL)-1])
+	if len(q.Args) > 0 {
+		i.addArgs(q.Args...)
+	}
+	return nil
 }
 
 // Columns specifies the columns that need to be inserted
@@ -112,8 +172,97 @@ func (i *Inserter[T]) Values(values ...*T) *Inserter[T] {
 	return i
 }
 
+// Select 使用查询的结果作为插入的数据，即 INSERT INTO xxx SELECT
+// 一般来说，q 是一个 Selector，它所查询的列数必须和插入的列数一致
+// 设置了 Select 之后，Values 将会被忽略
+func (i *Inserter[T]) Select(q QueryBuilder) *Inserter[T] {
+	i.src = q
+	return i
+}
+
+// Ignore 冲突的时候忽略该行
+// 在 MySQL 里面是 INSERT IGNORE，在 SQLite 里面是 INSERT OR IGNORE
+func (i *Inserter[T]) Ignore() *Inserter[T] {
+	i.mode = dialect.InsertModeIgnore
+	return i
+}
+
+// Replace 冲突的时候使用新的行替换旧的行
+// 在 MySQL 里面是 REPLACE INTO，在 SQLite 里面是 INSERT OR REPLACE
+func (i *Inserter[T]) Replace() *Inserter[T] {
+	i.mode = dialect.InsertModeReplace
+	return i
+}
+
+// OnDuplicateKey 开始构造冲突之后的更新部分
+// 在 MySQL 里面，会根据所有的唯一索引来判断冲突
+// 需要指定冲突列的时候，请使用 OnConflict
+func (i *Inserter[T]) OnDuplicateKey() *OnDuplicateKeyBuilder[*Inserter[T]] {
+	return &OnDuplicateKeyBuilder[*Inserter[T]]{
+		inserter: i,
+		attr:     &i.inserterBuilderAttribute,
+	}
+}
+
+// OnConflict 开始构造冲突之后的更新部分，cs 是冲突列的字段名
+// 在 SQLite 和 PostgreSQL 里面会生成 ON CONFLICT(xxx) DO UPDATE SET，
+// MySQL 会忽略冲突列
+func (i *Inserter[T]) OnConflict(cs ...string) *OnDuplicateKeyBuilder[*Inserter[T]] {
+	return &OnDuplicateKeyBuilder[*Inserter[T]]{
+		inserter:        i,
+		attr:            &i.inserterBuilderAttribute,
+		conflictColumns: cs,
+	}
+}
+
+// Returning 指定 RETURNING 子句中返回的字段
+// 并不是所有的方言都支持 RETURNING，例如 MySQL 就不支持
+func (i *Inserter[T]) Returning(fields ...string) *Inserter[T] {
+	i.returning = fields
+	return i
+}
+
+// FillPK 执行之后，将自增主键回填到 Values 传入的每一个元素中
+// 在支持 LastInsertId 的方言中，例如 MySQL 和 SQLite，会根据 LastInsertId 和插入的行数来计算主键，
+// 所以要求主键是连续分配的；否则，例如 PostgreSQL，会使用 RETURNING 来获得主键
+// 已经指定了主键的元素不会被回填，但是在使用 LastInsertId 的方言中，
+// 同一个语句里面不能同时有指定了主键和没有指定主键的元素
+// 要求模型有且只有一个主键，并且不能和 Ignore、Replace 或者 upsert 一起使用
+func (i *Inserter[T]) FillPK() *Inserter[T] {
+	i.fillPK = true
+	return i
+}
+
+// Batch 开启分批插入，Values 会被切分为多批，每一批最多 size 行
+// 每一批都会生成一个 INSERT 语句，并且按照顺序执行
+func (i *Inserter[T]) Batch(size int) *Inserter[T] {
+	i.batchSize = size
+	return i
+}
+
+// BatchByArgs 开启分批插入，每一批的参数个数不超过 limit
+// 例如 MySQL 中一个语句最多只能有 65535 个占位符
+// 和 Batch 一起使用的时候，取两者中较小的行数
+func (i *Inserter[T]) BatchByArgs(limit int) *Inserter[T] {
+	i.batchArgs = limit
+	return i
+}
+
+// BatchInTx 分批插入的时候，在同一个事务里面执行所有的批次，失败的时候回滚并且返回空的结果
+// 如果 Inserter 本身就是在事务中创建的，那么直接使用该事务，失败的时候不会回滚，由用户决定
+func (i *Inserter[T]) BatchInTx() *Inserter[T] {
+	i.batchInTx = true
+	return i
+}
+
 // Exec 发起查询
 func (i *Inserter[T]) Exec(ctx context.Context) Result {
+	if i.src == nil && (i.batchSize > 0 || i.batchArgs > 0) {
+		return i.execBatch(ctx)
+	}
+	if i.fillPK {
+		return i.execAndFillPK(ctx)
+	}
 	query, err := i.Build()
 	if err != nil {
 		return Result{err: err}
@@ -121,6 +270,222 @@ func (i *Inserter[T]) Exec(ctx context.Context) Result {
 	return newQuerier[T](i.db, query, i.meta, INSERT).Exec(ctx)
 }
 
+// execBatch 分批执行，遇到错误的时候立刻返回，
+// 如果是在事务中执行的，那么会回滚整个事务
+func (i *Inserter[T]) execBatch(ctx context.Context) (res Result) {
+	defer bytebufferpool.Put(i.buffer)
+	if len(i.values) == 0 {
+		return Result{err: errors.New("插入0行")}
+	}
+	meta, err := i.metaRegistry.Get(new(T))
+	if err != nil {
+		return Result{err: err}
+	}
+	cols := len(i.columns)
+	if cols == 0 {
+		for _, c := range meta.Columns {
+			if !(i.ignorePK && c.IsPrimaryKey) {
+				cols++
+			}
+		}
+	}
+	fixed, err := upsertArgs(i.core, meta, i.upsert)
+	if err != nil {
+		return Result{err: err}
+	}
+	size, err := i.chunkSize(cols, fixed)
+	if err != nil {
+		return Result{err: err}
+	}
+
+	sess := i.db
+	if i.batchInTx {
+		switch s := sess.(type) {
+		case *DB:
+			tx, err := s.BeginTx(ctx, nil)
+			if err != nil {
+				return Result{err: err}
+			}
+			defer func() {
+				if res.err == nil {
+					res.err = tx.Commit()
+				} else {
+					_ = tx.Rollback()
+				}
+				// 回滚之后，前面批次的结果也就没有意义了
+				if res.err != nil {
+					res.res = nil
+				}
+			}()
+			sess = tx
+		case *Tx:
+			// 已经在事务中，是否回滚由用户决定
+		default:
+			return Result{err: errs.ErrBatchInTxUnsupported}
+		}
+	}
+
+	attr := i.inserterBuilderAttribute
+	attr.batchSize, attr.batchArgs = 0, 0
+	results := make(batchResult, 0, (len(i.values)+size-1)/size)
+	for start := 0; start < len(i.values); start += size {
+		end := start + size
+		if end > len(i.values) {
+			end = len(i.values)
+		}
+		chunk := &Inserter[T]{
+			inserterBuilder: inserterBuilder{
+				builder: builder{
+					core:   sess.getCore(),
+					buffer: bytebufferpool.Get(),
+				},
+				inserterBuilderAttribute: attr,
+			},
+			db:     sess,
+			values: i.values[start:end],
+		}
+		r := chunk.Exec(ctx)
+		if r.err != nil {
+			return Result{err: r.err, res: results}
+		}
+		results = append(results, r.res)
+	}
+	return Result{res: results}
+}
+
+// execAndFillPK 执行查询，并且回填自增主键
+func (i *Inserter[T]) execAndFillPK(ctx context.Context) Result {
+	meta, err := i.metaRegistry.Get(new(T))
+	if err != nil {
+		return Result{err: err}
+	}
+	var pk *model.ColumnMeta
+	for _, c := range meta.Columns {
+		if !c.IsPrimaryKey {
+			continue
+		}
+		if pk != nil {
+			return Result{err: errs.ErrSinglePrimaryKeyOnly}
+		}
+		pk = c
+	}
+	if pk == nil {
+		return Result{err: errs.ErrSinglePrimaryKeyOnly}
+	}
+	// 有一些行可能没有插入，或者插入的是已有的行，这时候无法确定每一行的主键
+	if i.mode != dialect.InsertModeDefault || i.upsert != nil {
+		return Result{err: errs.ErrFillPKWithConflict}
+	}
+	if !i.dialect.SupportLastInsertID() {
+		return i.execReturningPK(ctx, pk)
+	}
+
+	// 只有没有指定主键的行才会使用自增主键。
+	// 混合了指定主键的行的时候，自增主键不一定连续，
+	// 例如 SQLite 的 LastInsertId 可能就是指定的主键，所以无法计算每一行的主键
+	fds := make([]reflect.Value, 0, len(i.values))
+	for _, val := range i.values {
+		fd, err := i.valCreator.NewPrimitiveValue(val, meta).Field(pk.FieldName)
+		if err != nil {
+			return Result{err: err}
+		}
+		if i.explicitPK(pk, fd) {
+			continue
+		}
+		fds = append(fds, fd)
+	}
+	if len(fds) > 0 && len(fds) < len(i.values) {
+		return Result{err: errs.ErrFillPKMixedExplicit}
+	}
+
+	query, err := i.Build()
+	if err != nil {
+		return Result{err: err}
+	}
+	res := newQuerier[T](i.db, query, i.meta, INSERT).Exec(ctx)
+	if res.err != nil || len(fds) == 0 {
+		return res
+	}
+	lastID, err := res.res.LastInsertId()
+	if err != nil {
+		return Result{err: err, res: res.res}
+	}
+	firstID := i.dialect.FirstInsertID(lastID, int64(len(fds)))
+	for idx, fd := range fds {
+		// 主键在 nil 的指针组合里面，没有地方回写
+		if !fd.CanAddr() {
+			continue
+		}
+		if err = rows.ConvertAssign(fd.Addr().Interface(), firstID+int64(idx)); err != nil {
+			return Result{err: err, res: res.res}
+		}
+	}
+	return res
+}
+
+// explicitPK 该行是否插入了用户指定的主键，
+// 如果插入的列里面没有主键，那么主键都是数据库生成的
+func (i *Inserter[T]) explicitPK(pk *model.ColumnMeta, fd reflect.Value) bool {
+	if len(i.columns) == 0 {
+		return !i.ignorePK && !fd.IsZero()
+	}
+	for _, c := range i.columns {
+		if c == pk.FieldName {
+			return !fd.IsZero()
+		}
+	}
+	return false
+}
+
+// execReturningPK 借助 RETURNING 获得自增主键，
+// 要求返回的行的顺序和插入的顺序一致
+func (i *Inserter[T]) execReturningPK(ctx context.Context, pk *model.ColumnMeta) Result {
+	defer func(returning []string) {
+		i.returning = returning
+	}(i.returning)
+	i.returning = []string{pk.FieldName}
+	query, err := i.Build()
+	if err != nil {
+		return Result{err: err}
+	}
+	var handler HandleFunc = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		rs, err := i.db.queryContext(ctx, qc.q)
+		if err != nil {
+			return &QueryResult{Err: err}
+		}
+		defer func() {
+			_ = rs.Close()
+		}()
+		var res returningResult
+		for rs.Next() && res.rowsAffected < int64(len(i.values)) {
+			if err = rs.Scan(&res.lastInsertID); err != nil {
+				return &QueryResult{Err: err}
+			}
+			fd, err := i.valCreator.NewPrimitiveValue(i.values[res.rowsAffected], qc.meta).Field(pk.FieldName)
+			if err != nil {
+				return &QueryResult{Err: err}
+			}
+			if fd.CanAddr() && !i.explicitPK(pk, fd) {
+				if err = rows.ConvertAssign(fd.Addr().Interface(), res.lastInsertID); err != nil {
+					return &QueryResult{Err: err}
+				}
+			}
+			res.rowsAffected++
+		}
+		return &QueryResult{Result: res, Err: rs.Err()}
+	}
+	ms := i.ms
+	for j := len(ms) - 1; j >= 0; j-- {
+		handler = ms[j](handler)
+	}
+	qr := handler(ctx, i.withComment(ctx, &QueryContext{q: query, meta: i.meta, Type: INSERT}))
+	var res sql.Result
+	if qr.Result != nil {
+		res = qr.Result.(sql.Result)
+	}
+	return Result{err: qr.Err, res: res}
+}
+
 func (i *Inserter[T]) buildColumns() ([]*model.ColumnMeta, error) {
 	cs := make([]*model.ColumnMeta, 0, len(i.columns))
 	if len(i.columns) != 0 {
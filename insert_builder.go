@@ -14,9 +14,78 @@
 
 package eorm
 
+import (
+	"reflect"
+	"time"
+
+	"github.com/ecodeclub/eorm/internal/dialect"
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/ecodeclub/eorm/internal/model"
+	"github.com/valyala/bytebufferpool"
+)
+
 type inserterBuilderAttribute struct {
-	columns  []string
-	ignorePK bool
+	columns   []string
+	ignorePK  bool
+	returning []string
+	upsert    *Upsert
+	mode      dialect.InsertMode
+	fillPK    bool
+	// batchSize 是分批插入时每一批的最大行数
+	batchSize int
+	// batchArgs 是分批插入时每一批的最大参数个数
+	batchArgs int
+}
+
+// chunkSize 根据每一行的列数 cols，以及每一批都有的参数个数 fixed，例如 upsert 中的参数，
+// 计算分批插入时每一批的行数
+// 返回 0 代表不需要分批；连一行都放不下的时候返回错误
+func (a *inserterBuilderAttribute) chunkSize(cols int, fixed int) (int, error) {
+	if a.batchSize <= 0 && a.batchArgs <= 0 {
+		return 0, nil
+	}
+	size := a.batchSize
+	if a.batchArgs > 0 && cols > 0 {
+		byArgs := (a.batchArgs - fixed) / cols
+		if size <= 0 || byArgs < size {
+			size = byArgs
+		}
+	}
+	if size < 1 {
+		return 0, errs.ErrInvalidBatchSize
+	}
+	return size, nil
+}
+
+// upsertArgs 计算 upsert 部分的参数个数
+func upsertArgs(c core, meta *model.TableMeta, upsert *Upsert) (int, error) {
+	if upsert == nil {
+		return 0, nil
+	}
+	b := builder{
+		core:   c,
+		buffer: bytebufferpool.Get(),
+		meta:   meta,
+	}
+	defer bytebufferpool.Put(b.buffer)
+	if err := b.buildUpsert(upsert); err != nil {
+		return 0, err
+	}
+	return len(b.args), nil
+}
+
+// columnCounter 用于获得 SELECT 语句的列数，必须在 Build 之后调用
+// 无法确定列数的时候，返回 -1
+type columnCounter interface {
+	selectedColumnCount() int
+}
+
+// insertValue 返回插入的值，零值的 autoCreateTime 和 autoUpdateTime 列使用当前时间
+func insertValue(c *model.ColumnMeta, fd reflect.Value, now time.Time) any {
+	if (c.AutoCreateTime || c.AutoUpdateTime) && fd.IsZero() {
+		return c.TimeValue(now)
+	}
+	return fd.Interface()
 }
 
 type inserterBuilder struct {
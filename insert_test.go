@@ -15,11 +15,17 @@ package eorm
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ecodeclub/eorm/internal/datasource/single"
+	"github.com/ecodeclub/eorm/internal/errs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInserter_Values(t *testing.T) {
@@ -274,6 +280,321 @@ func TestInserter_ValuesForCombination(t *testing.T) {
 	}
 }
 
+type autoTimeModel struct {
+	Id         int `eorm:"primary_key"`
+	Name       string
+	CreateTime int64 `eorm:"autoCreateTime"`
+	UpdateTime int64 `eorm:"autoUpdateTime:milli"`
+}
+
+func TestInserter_AutoTime(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	db, err := Open("sqlite3", "file:test.db?cache=shared&mode=memory",
+		DBWithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name:     "zero value",
+			builder:  NewInserter[autoTimeModel](db).Values(&autoTimeModel{Id: 1, Name: "Tom"}),
+			wantSql:  "INSERT INTO `auto_time_model`(`id`,`name`,`create_time`,`update_time`) VALUES(?,?,?,?);",
+			wantArgs: []interface{}{1, "Tom", int64(1700000000), int64(1700000000123)},
+		},
+		{
+			name:     "keep non zero value",
+			builder:  NewInserter[autoTimeModel](db).Values(&autoTimeModel{Id: 1, Name: "Tom", CreateTime: 100, UpdateTime: 200}),
+			wantSql:  "INSERT INTO `auto_time_model`(`id`,`name`,`create_time`,`update_time`) VALUES(?,?,?,?);",
+			wantArgs: []interface{}{1, "Tom", int64(100), int64(200)},
+		},
+		{
+			name:     "columns",
+			builder:  NewInserter[autoTimeModel](db).Columns("Id", "CreateTime").Values(&autoTimeModel{Id: 1}),
+			wantSql:  "INSERT INTO `auto_time_model`(`id`,`create_time`) VALUES(?,?);",
+			wantArgs: []interface{}{1, int64(1700000000)},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestInserter_Returning(t *testing.T) {
+	u := &TestModel{Id: 12, FirstName: "Tom", Age: 18}
+	pg := postgresDB(t)
+	mysqlDB, err := OpenDS("mysql", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []CommonTestCase{
+		{
+			name:     "postgres",
+			builder:  NewInserter[TestModel](pg).Columns("FirstName", "Age").Values(u).Returning("Id"),
+			wantSql:  `INSERT INTO "test_model"("first_name","age") VALUES($1,$2) RETURNING "id";`,
+			wantArgs: []interface{}{"Tom", int8(18)},
+		},
+		{
+			name:     "sqlite",
+			builder:  NewInserter[TestModel](memoryDB()).Columns("FirstName").Values(u).Returning("Id", "Age"),
+			wantSql:  "INSERT INTO `test_model`(`first_name`) VALUES(?) RETURNING `id`,`age`;",
+			wantArgs: []interface{}{"Tom"},
+		},
+		{
+			name:    "invalid field",
+			builder: NewInserter[TestModel](pg).Values(u).Returning("Invalid"),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "mysql unsupported",
+			builder: NewInserter[TestModel](mysqlDB).Values(u).Returning("Id"),
+			wantErr: errs.NewUnsupportedClauseError("MySQL", "RETURNING"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.EqualValues(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestInserter_Upsert(t *testing.T) {
+	u := &TestModel{Id: 12, FirstName: "Tom", Age: 18}
+	sqlite := memoryDB()
+	pg := postgresDB(t)
+	mysqlDB, err := OpenDS("mysql", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []CommonTestCase{
+		{
+			name:     "mysql columns",
+			builder:  NewInserter[TestModel](mysqlDB).Columns("Id", "FirstName", "Age").Values(u).OnDuplicateKey().Update(Columns("FirstName", "Age")),
+			wantSql:  "INSERT INTO `test_model`(`id`,`first_name`,`age`) VALUES(?,?,?) ON DUPLICATE KEY UPDATE `first_name`=VALUES(`first_name`),`age`=VALUES(`age`);",
+			wantArgs: []interface{}{int64(12), "Tom", int8(18)},
+		},
+		{
+			name: "mysql assign",
+			builder: NewInserter[TestModel](mysqlDB).Columns("Id", "FirstName").Values(u).
+				OnDuplicateKey().Update(Assign("FirstName", "Jerry"), C("Age"), Assign("Age", C("Age").Add(1))),
+			wantSql:  "INSERT INTO `test_model`(`id`,`first_name`) VALUES(?,?) ON DUPLICATE KEY UPDATE `first_name`=?,`age`=VALUES(`age`),`age`=(`age`+?);",
+			wantArgs: []interface{}{int64(12), "Tom", "Jerry", 1},
+		},
+		{
+			name:     "mysql ignore conflict columns",
+			builder:  NewInserter[TestModel](mysqlDB).Columns("Id", "Age").Values(u).OnConflict("Id").Update(C("Age")),
+			wantSql:  "INSERT INTO `test_model`(`id`,`age`) VALUES(?,?) ON DUPLICATE KEY UPDATE `age`=VALUES(`age`);",
+			wantArgs: []interface{}{int64(12), int8(18)},
+		},
+		{
+			name:     "sqlite",
+			builder:  NewInserter[TestModel](sqlite).Columns("Id", "FirstName", "Age").Values(u).OnConflict("Id").Update(Columns("FirstName", "Age")),
+			wantSql:  "INSERT INTO `test_model`(`id`,`first_name`,`age`) VALUES(?,?,?) ON CONFLICT(`id`) DO UPDATE SET `first_name`=excluded.`first_name`,`age`=excluded.`age`;",
+			wantArgs: []interface{}{int64(12), "Tom", int8(18)},
+		},
+		{
+			name:     "sqlite without conflict columns",
+			builder:  NewInserter[TestModel](sqlite).Columns("Id", "Age").Values(u).OnDuplicateKey().Update(Assign("Age", 20)),
+			wantSql:  "INSERT INTO `test_model`(`id`,`age`) VALUES(?,?) ON CONFLICT DO UPDATE SET `age`=?;",
+			wantArgs: []interface{}{int64(12), int8(18), 20},
+		},
+		{
+			name:     "postgres",
+			builder:  NewInserter[TestModel](pg).Columns("Id", "Age").Values(u).OnConflict("Id").Update(C("Age"), Assign("FirstName", "Jerry")).Returning("Id"),
+			wantSql:  `INSERT INTO "test_model"("id","age") VALUES($1,$2) ON CONFLICT("id") DO UPDATE SET "age"=excluded."age","first_name"=$3 RETURNING "id";`,
+			wantArgs: []interface{}{int64(12), int8(18), "Jerry"},
+		},
+		{
+			name:    "postgres without conflict columns",
+			builder: NewInserter[TestModel](pg).Values(u).OnDuplicateKey().Update(C("Age")),
+			wantErr: errs.ErrMissingConflictColumns,
+		},
+		{
+			name:    "invalid conflict column",
+			builder: NewInserter[TestModel](sqlite).Values(u).OnConflict("Invalid").Update(C("Age")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid update column",
+			builder: NewInserter[TestModel](sqlite).Values(u).OnConflict("Id").Update(Columns("Age", "Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "no update column",
+			builder: NewInserter[TestModel](sqlite).Values(u).OnConflict("Id").Update(),
+			wantErr: errs.NewValueNotSetError(),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.EqualValues(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestInserter_Mode(t *testing.T) {
+	u := &TestModel{Id: 12, FirstName: "Tom", Age: 18}
+	sqlite := memoryDB()
+	pg := postgresDB(t)
+	mysqlDB, err := OpenDS("mysql", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []CommonTestCase{
+		{
+			name:     "mysql ignore",
+			builder:  NewInserter[TestModel](mysqlDB).Columns("Id", "Age").Values(u).Ignore(),
+			wantSql:  "INSERT IGNORE INTO `test_model`(`id`,`age`) VALUES(?,?);",
+			wantArgs: []interface{}{int64(12), int8(18)},
+		},
+		{
+			name:     "mysql replace",
+			builder:  NewInserter[TestModel](mysqlDB).Columns("Id", "Age").Values(u).Replace(),
+			wantSql:  "REPLACE INTO `test_model`(`id`,`age`) VALUES(?,?);",
+			wantArgs: []interface{}{int64(12), int8(18)},
+		},
+		{
+			name:     "sqlite ignore",
+			builder:  NewInserter[TestModel](sqlite).Columns("Id", "Age").Values(u).Ignore(),
+			wantSql:  "INSERT OR IGNORE INTO `test_model`(`id`,`age`) VALUES(?,?);",
+			wantArgs: []interface{}{int64(12), int8(18)},
+		},
+		{
+			name:     "sqlite replace",
+			builder:  NewInserter[TestModel](sqlite).Columns("Id", "Age").Values(u).Replace(),
+			wantSql:  "INSERT OR REPLACE INTO `test_model`(`id`,`age`) VALUES(?,?);",
+			wantArgs: []interface{}{int64(12), int8(18)},
+		},
+		{
+			name:     "postgres ignore",
+			builder:  NewInserter[TestModel](pg).Columns("Id", "Age").Values(u).Ignore(),
+			wantSql:  `INSERT INTO "test_model"("id","age") VALUES($1,$2) ON CONFLICT DO NOTHING;`,
+			wantArgs: []interface{}{int64(12), int8(18)},
+		},
+		{
+			name:    "postgres replace",
+			builder: NewInserter[TestModel](pg).Values(u).Replace(),
+			wantErr: errs.NewUnsupportedClauseError("PostgreSQL", "REPLACE"),
+		},
+		{
+			name:    "ignore with upsert",
+			builder: NewInserter[TestModel](mysqlDB).Values(u).Ignore().OnDuplicateKey().Update(C("Age")),
+			wantErr: errs.ErrInsertModeWithUpsert,
+		},
+		{
+			name:    "replace with upsert",
+			builder: NewInserter[TestModel](sqlite).Values(u).Replace().OnConflict("Id").Update(C("Age")),
+			wantErr: errs.ErrInsertModeWithUpsert,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.EqualValues(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestInserter_Select(t *testing.T) {
+	type TestModelArchive struct {
+		Id        int64 `eorm:"auto_increment,primary_key"`
+		FirstName string
+		Age       int8
+		LastName  *sql.NullString
+	}
+	db := memoryDB()
+	pg := postgresDB(t)
+	testCases := []CommonTestCase{
+		{
+			name:    "all columns",
+			builder: NewInserter[TestModelArchive](db).Select(NewSelector[TestModel](db)),
+			wantSql: "INSERT INTO `test_model_archive`(`id`,`first_name`,`age`,`last_name`) SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model`;",
+		},
+		{
+			name: "specify columns",
+			builder: NewInserter[TestModelArchive](db).Columns("Id", "Age").
+				Select(NewSelector[TestModel](db).Select(Columns("Id"), C("Age")).Where(C("Age").GT(18))),
+			wantSql:  "INSERT INTO `test_model_archive`(`id`,`age`) SELECT `id`,`age` FROM `test_model` WHERE `age`>?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "ignore values",
+			builder: NewInserter[TestModelArchive](db).Columns("Id").Values(&TestModelArchive{Id: 1}).
+				Select(NewSelector[TestModel](db).Select(C("Id"))),
+			wantSql: "INSERT INTO `test_model_archive`(`id`) SELECT `id` FROM `test_model`;",
+		},
+		{
+			name: "raw select",
+			builder: NewInserter[TestModelArchive](db).Columns("Id", "Age").
+				Select(NewSelector[TestModel](db).Select(Raw("*"))),
+			wantSql: "INSERT INTO `test_model_archive`(`id`,`age`) SELECT * FROM `test_model`;",
+		},
+		{
+			name: "upsert",
+			builder: NewInserter[TestModelArchive](pg).Columns("Id", "Age").
+				Select(NewSelector[TestModel](pg).Select(Columns("Id", "Age")).Where(C("Age").GT(18))).
+				OnConflict("Id").Update(Assign("Age", 20)),
+			wantSql:  `INSERT INTO "test_model_archive"("id","age") SELECT "id","age" FROM "test_model" WHERE "age">$1 ON CONFLICT("id") DO UPDATE SET "age"=$2;`,
+			wantArgs: []interface{}{18, 20},
+		},
+		{
+			name: "column count mismatch",
+			builder: NewInserter[TestModelArchive](db).Columns("Id", "Age").
+				Select(NewSelector[TestModel](db).Select(C("Id"))),
+			wantErr: errs.NewColumnCountMismatchError(2, 1),
+		},
+		{
+			name: "invalid select",
+			builder: NewInserter[TestModelArchive](db).
+				Select(NewSelector[TestModel](db).Select(C("Invalid"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.EqualValues(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
 func TestInserter_Exec(t *testing.T) {
 	orm := memoryDB()
 	testCases := []struct {
@@ -312,6 +633,395 @@ func TestInserter_Exec(t *testing.T) {
 	}
 }
 
+func TestInserter_FillPK(t *testing.T) {
+	testCases := []struct {
+		name      string
+		driver    string
+		mockOrder func(mock sqlmock.Sqlmock)
+		values    []*TestModel
+		wantIds   []int64
+		wantErr   error
+	}{
+		{
+			name:   "mysql",
+			driver: "mysql",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`,`age`,`last_name`) VALUES(?,?,?),(?,?,?);").
+					WillReturnResult(sqlmock.NewResult(10, 2))
+			},
+			values:  []*TestModel{{FirstName: "Tom"}, {FirstName: "Jerry"}},
+			wantIds: []int64{10, 11},
+		},
+		{
+			name:   "sqlite",
+			driver: "sqlite3",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`,`age`,`last_name`) VALUES(?,?,?),(?,?,?),(?,?,?);").
+					WillReturnResult(sqlmock.NewResult(12, 3))
+			},
+			values:  []*TestModel{{FirstName: "Tom"}, {FirstName: "Jerry"}, {FirstName: "Bob"}},
+			wantIds: []int64{10, 11, 12},
+		},
+		{
+			name:   "postgres",
+			driver: "postgres",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO "test_model"("first_name","age","last_name") VALUES($1,$2,$3),($4,$5,$6) RETURNING "id";`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20).AddRow(21))
+			},
+			values:  []*TestModel{{FirstName: "Tom"}, {FirstName: "Jerry"}},
+			wantIds: []int64{20, 21},
+		},
+		{
+			name:   "exec error",
+			driver: "mysql",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`,`age`,`last_name`) VALUES(?,?,?);").
+					WillReturnError(errors.New("mock error"))
+			},
+			values:  []*TestModel{{FirstName: "Tom"}},
+			wantIds: []int64{0},
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name:   "query error",
+			driver: "postgres",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO "test_model"("first_name","age","last_name") VALUES($1,$2,$3) RETURNING "id";`).
+					WillReturnError(errors.New("mock error"))
+			},
+			values:  []*TestModel{{FirstName: "Tom"}},
+			wantIds: []int64{0},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := OpenDS(tc.driver, single.NewDB(mockDB))
+			require.NoError(t, err)
+			tc.mockOrder(mock)
+
+			res := NewInserter[TestModel](db).SkipPK().Values(tc.values...).FillPK().Exec(context.Background())
+			assert.Equal(t, tc.wantErr, res.Err())
+			ids := make([]int64, 0, len(tc.values))
+			for _, v := range tc.values {
+				ids = append(ids, v.Id)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+			if res.Err() != nil {
+				return
+			}
+			affected, err := res.RowsAffected()
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.values)), affected)
+		})
+	}
+}
+
+func TestInserter_FillPK_ExplicitPK(t *testing.T) {
+	testCases := []struct {
+		name      string
+		driver    string
+		mockOrder func(mock sqlmock.Sqlmock)
+		values    []*TestModel
+		wantIds   []int64
+		wantErr   error
+	}{
+		{
+			name:   "mysql zero pk",
+			driver: "mysql",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `test_model`(`id`,`first_name`,`age`,`last_name`) VALUES(?,?,?,?),(?,?,?,?);").
+					WillReturnResult(sqlmock.NewResult(10, 2))
+			},
+			values:  []*TestModel{{}, {}},
+			wantIds: []int64{10, 11},
+		},
+		{
+			name:   "mysql explicit pk",
+			driver: "mysql",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `test_model`(`id`,`first_name`,`age`,`last_name`) VALUES(?,?,?,?),(?,?,?,?);").
+					WillReturnResult(sqlmock.NewResult(6, 2))
+			},
+			values:  []*TestModel{{Id: 5}, {Id: 6}},
+			wantIds: []int64{5, 6},
+		},
+		{
+			// 计数器是 10 的时候，MySQL 插入的主键是 11, 100, 101
+			name:      "mysql mixed",
+			driver:    "mysql",
+			mockOrder: func(mock sqlmock.Sqlmock) {},
+			values:    []*TestModel{{}, {Id: 100}, {}},
+			wantIds:   []int64{0, 100, 0},
+			wantErr:   errs.ErrFillPKMixedExplicit,
+		},
+		{
+			// 最后一行指定了主键的时候，SQLite 的 LastInsertId 就是这个主键
+			name:      "sqlite mixed",
+			driver:    "sqlite3",
+			mockOrder: func(mock sqlmock.Sqlmock) {},
+			values:    []*TestModel{{}, {}, {Id: 5}},
+			wantIds:   []int64{0, 0, 5},
+			wantErr:   errs.ErrFillPKMixedExplicit,
+		},
+		{
+			name:   "postgres mixed",
+			driver: "postgres",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO "test_model"("id","first_name","age","last_name") VALUES($1,$2,$3,$4),($5,$6,$7,$8),($9,$10,$11,$12) RETURNING "id";`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11).AddRow(100).AddRow(101))
+			},
+			values:  []*TestModel{{}, {Id: 100}, {}},
+			wantIds: []int64{11, 100, 101},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := OpenDS(tc.driver, single.NewDB(mockDB))
+			require.NoError(t, err)
+			tc.mockOrder(mock)
+
+			ins := NewInserter[TestModel](db).Values(tc.values...).FillPK()
+			res := ins.Exec(context.Background())
+			assert.Equal(t, tc.wantErr, res.Err())
+			// RETURNING 只是用来回填主键的，不能影响用户的 Inserter
+			assert.Nil(t, ins.returning)
+			ids := make([]int64, 0, len(tc.values))
+			for _, v := range tc.values {
+				ids = append(ids, v.Id)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInserter_FillPK_Conflict(t *testing.T) {
+	db, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
+	testCases := []struct {
+		name     string
+		inserter *Inserter[TestModel]
+	}{
+		{
+			name:     "ignore",
+			inserter: NewInserter[TestModel](db).Values(&TestModel{}).Ignore(),
+		},
+		{
+			name:     "replace",
+			inserter: NewInserter[TestModel](db).Values(&TestModel{}).Replace(),
+		},
+		{
+			name:     "upsert",
+			inserter: NewInserter[TestModel](db).Values(&TestModel{}).OnDuplicateKey().Update(C("Age")),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.inserter.FillPK().Exec(context.Background())
+			assert.Equal(t, errs.ErrFillPKWithConflict, res.Err())
+		})
+	}
+}
+
+func TestInserter_FillPK_NoPK(t *testing.T) {
+	type NoPK struct {
+		Name string
+	}
+	type CompositePK struct {
+		Id   int64  `eorm:"primary_key"`
+		Name string `eorm:"primary_key"`
+	}
+	db := memoryDB()
+	res := NewInserter[NoPK](db).Values(&NoPK{}).FillPK().Exec(context.Background())
+	assert.Equal(t, errs.ErrSinglePrimaryKeyOnly, res.Err())
+	res = NewInserter[CompositePK](db).Values(&CompositePK{}).FillPK().Exec(context.Background())
+	assert.Equal(t, errs.ErrSinglePrimaryKeyOnly, res.Err())
+}
+
+func TestInserter_Batch(t *testing.T) {
+	testCases := []struct {
+		name         string
+		mockOrder    func(mock sqlmock.Sqlmock)
+		inserter     func(db *DB) *Inserter[TestModel]
+		wantAffected int64
+		wantErr      error
+	}{
+		{
+			name: "batch by rows",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`,`age`,`last_name`) VALUES(?,?,?),(?,?,?);").
+					WithArgs("a", int8(0), nil, "b", int8(0), nil).
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`,`age`,`last_name`) VALUES(?,?,?);").
+					WithArgs("c", int8(0), nil).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+			},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).SkipPK().
+					Values(&TestModel{FirstName: "a"}, &TestModel{FirstName: "b"}, &TestModel{FirstName: "c"}).
+					Batch(2)
+			},
+			wantAffected: 3,
+		},
+		{
+			name: "batch by args",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?),(?);").
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?);").
+					WillReturnResult(sqlmock.NewResult(3, 1))
+			},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).Columns("FirstName").
+					Values(&TestModel{FirstName: "a"}, &TestModel{FirstName: "b"}, &TestModel{FirstName: "c"}).
+					Batch(10).BatchByArgs(2)
+			},
+			wantAffected: 3,
+		},
+		{
+			name: "in tx",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?);").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?);").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).Columns("FirstName").
+					Values(&TestModel{FirstName: "a"}, &TestModel{FirstName: "b"}).
+					Batch(1).BatchInTx()
+			},
+			wantAffected: 2,
+		},
+		{
+			name: "rollback",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?);").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?);").
+					WillReturnError(errors.New("mock error"))
+				mock.ExpectRollback()
+			},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).Columns("FirstName").
+					Values(&TestModel{FirstName: "a"}, &TestModel{FirstName: "b"}).
+					Batch(1).BatchInTx()
+			},
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name: "existing tx",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?);").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?);").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+			},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				tx, err := db.BeginTx(context.Background(), nil)
+				require.NoError(t, err)
+				return NewInserter[TestModel](tx).Columns("FirstName").
+					Values(&TestModel{FirstName: "a"}, &TestModel{FirstName: "b"}).
+					Batch(1).BatchInTx()
+			},
+			wantAffected: 2,
+		},
+		{
+			name: "commit error",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?);").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+			},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).Columns("FirstName").
+					Values(&TestModel{FirstName: "a"}).Batch(1).BatchInTx()
+			},
+			wantErr: errors.New("commit error"),
+		},
+		{
+			name: "batch by args with upsert",
+			mockOrder: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?),(?) ON DUPLICATE KEY UPDATE `age`=?;").
+					WithArgs("a", "b", 18).
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectExec("INSERT INTO `test_model`(`first_name`) VALUES(?) ON DUPLICATE KEY UPDATE `age`=?;").
+					WithArgs("c", 18).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+			},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).Columns("FirstName").
+					Values(&TestModel{FirstName: "a"}, &TestModel{FirstName: "b"}, &TestModel{FirstName: "c"}).
+					BatchByArgs(3).OnDuplicateKey().Update(Assign("Age", 18))
+			},
+			wantAffected: 3,
+		},
+		{
+			name:      "args less than one row",
+			mockOrder: func(mock sqlmock.Sqlmock) {},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).SkipPK().Values(&TestModel{}).BatchByArgs(2)
+			},
+			wantErr: errs.ErrInvalidBatchSize,
+		},
+		{
+			name:      "args less than upsert",
+			mockOrder: func(mock sqlmock.Sqlmock) {},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).Columns("FirstName").Values(&TestModel{}).
+					BatchByArgs(1).OnDuplicateKey().Update(Assign("Age", 18))
+			},
+			wantErr: errs.ErrInvalidBatchSize,
+		},
+		{
+			name:      "no values",
+			mockOrder: func(mock sqlmock.Sqlmock) {},
+			inserter: func(db *DB) *Inserter[TestModel] {
+				return NewInserter[TestModel](db).Batch(1)
+			},
+			wantErr: errors.New("插入0行"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer func() { _ = mockDB.Close() }()
+			db, err := OpenDS("mysql", single.NewDB(mockDB))
+			require.NoError(t, err)
+			tc.mockOrder(mock)
+
+			res := tc.inserter(db).Exec(context.Background())
+			assert.Equal(t, tc.wantErr, res.Err())
+			assert.NoError(t, mock.ExpectationsWereMet())
+			if res.Err() != nil {
+				// 回滚之后不能返回前面批次的结果
+				assert.Nil(t, res.res)
+				return
+			}
+			affected, err := res.RowsAffected()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantAffected, affected)
+		})
+	}
+}
+
 func ExampleInserter_Build() {
 	db := memoryDB()
 	query, _ := NewInserter[TestModel](db).Values(&TestModel{
@@ -14,33 +14,534 @@
 
 package dialect
 
-import "github.com/ecodeclub/eorm/internal/errs"
+import (
+	"strconv"
 
-// Dialect specify config or behavior of special SQL dialects
-type Dialect struct {
-	Name string
-	// in MYSQL, it's "`"
-	Quote byte
+	"github.com/ecodeclub/eorm/internal/errs"
+)
+
+// Dialect 代表 SQL 方言。
+// 所有和具体数据库相关的 SQL 片段，都应该交给 Dialect 来生成，
+// 而 builder 只负责组织 SQL 的整体结构
+type Dialect interface {
+	// Name 方言的名字
+	Name() string
+	// Quote 用于包裹标识符，例如表名、列名和别名
+	Quote(name string) string
+	// Placeholder 返回第 index 个参数的占位符，index 从 1 开始
+	Placeholder(index int) string
+	// BuildLimitOffset 生成分页子句
+	// limit 或者 offset 小于等于 0 的时候，认为没有设置
+	BuildLimitOffset(w Writer, limit, offset int)
+	// BuildReturning 生成 RETURNING 子句，columns 是列名
+	// 不支持 RETURNING 的方言应该返回错误
+	BuildReturning(w Writer, columns []string) error
+	// BuildUpsert 生成 upsert 子句中位于赋值语句之前的部分
+	// 例如 MySQL 中的 ON DUPLICATE KEY UPDATE
+	// conflictColumns 是冲突列的列名，部分方言会忽略它
+	BuildUpsert(w Writer, conflictColumns []string) error
+	// InsertedValue 返回在 upsert 中引用待插入值的表达式，column 是列名
+	InsertedValue(column string) string
+	// BuildInsert 生成 INSERT 语句中位于表名之前的部分，例如 INSERT IGNORE INTO
+	BuildInsert(w Writer, mode InsertMode) error
+	// BuildInsertSuffix 生成 INSERT 语句中位于 VALUES 之后的部分
+	// 用于那些需要在语句末尾表达 InsertMode 的方言
+	BuildInsertSuffix(w Writer, mode InsertMode)
+	// SupportLastInsertID 驱动是否支持 LastInsertId
+	// 不支持的时候，需要借助 RETURNING 来获得自增主键
+	SupportLastInsertID() bool
+	// FirstInsertID 根据 LastInsertId 计算批量插入时第一行的自增主键，n 是插入的行数
+	FirstInsertID(lastInsertID int64, n int64) int64
+	// BuildLock 生成 SELECT 语句末尾的行锁子句，例如 FOR UPDATE NOWAIT
+	// 不支持行锁的方言应该返回错误
+	BuildLock(w Writer, lock Lock) error
+	// Func 返回函数 name 在该方言下的写法，name 是大写的标准函数名，例如 CONCAT
+	Func(name string) FuncTemplate
+	// SupportNullsOrder 是否支持 ORDER BY 中的 NULLS FIRST 和 NULLS LAST
+	SupportNullsOrder() bool
+	// SupportJoinMutation 是否支持在 UPDATE 和 DELETE 中使用 JOIN，
+	// 例如 UPDATE a JOIN b ON ... SET ... 和 DELETE a FROM a JOIN b ON ...
+	SupportJoinMutation() bool
+	// SupportDeleteOrderLimit 是否支持 DELETE 语句中的 ORDER BY 和 LIMIT
+	SupportDeleteOrderLimit() bool
+	// SupportJoin 是否支持 typ 类型的 JOIN，例如 JoinFullOuter
+	SupportJoin(typ string) bool
+	// BuildIndexHint 生成位于表名之后的索引提示，例如 FORCE INDEX (`idx_a`)
+	// 不支持索引提示的方言应该返回错误
+	BuildIndexHint(w Writer, hint IndexHint) error
 }
 
-var (
-	MySQL = Dialect{
-		Name:  "MySQL",
-		Quote: '`',
+// FuncTemplate 描述了一个函数调用的写法，
+// 最终生成的 SQL 是 Prefix + 参数1 + Sep + 参数2 + ... + Suffix
+// 例如 COALESCE(a,b) 就是 {"COALESCE(", ",", ")"}
+// 而 SQLite 中的字符串拼接 (a||b) 就是 {"(", "||", ")"}
+type FuncTemplate struct {
+	Prefix string
+	Sep    string
+	Suffix string
+}
+
+// 各个方言写法不同的函数
+const (
+	FuncConcat    = "CONCAT"
+	FuncLength    = "LENGTH"
+	FuncDate      = "DATE"
+	FuncNow       = "NOW"
+	FuncSubstring = "SUBSTRING"
+)
+
+// 并不是所有方言都支持的 JOIN
+const (
+	JoinFullOuter = "FULL OUTER JOIN"
+	// JoinStraight 是 MySQL 特有的 JOIN，强制优化器按照从左到右的顺序读取表
+	JoinStraight = "STRAIGHT_JOIN"
+)
+
+// InsertMode 代表 INSERT 语句在遇到冲突时候的处理方式
+type InsertMode int
+
+const (
+	// InsertModeDefault 普通的 INSERT，冲突时返回错误
+	InsertModeDefault InsertMode = iota
+	// InsertModeIgnore 冲突时忽略该行
+	InsertModeIgnore
+	// InsertModeReplace 冲突时删除旧的行，再插入新的行
+	InsertModeReplace
+)
+
+// LockStrength 代表行锁的强度
+type LockStrength int
+
+const (
+	// LockNone 不加锁
+	LockNone LockStrength = iota
+	// LockForUpdate 排他锁
+	LockForUpdate
+	// LockForShare 共享锁
+	LockForShare
+)
+
+func (l LockStrength) String() string {
+	switch l {
+	case LockForUpdate:
+		return "FOR UPDATE"
+	case LockForShare:
+		return "FOR SHARE"
+	default:
+		return ""
 	}
-	SQLite = Dialect{
-		Name:  "SQLite",
-		Quote: '`',
+}
+
+// LockWait 代表遇到已经被其它事务锁住的行的时候的处理方式
+type LockWait int
+
+const (
+	// LockWaitDefault 等待锁释放
+	LockWaitDefault LockWait = iota
+	// LockNoWait 不等待，直接返回错误
+	LockNoWait
+	// LockSkipLocked 跳过已经被锁住的行
+	LockSkipLocked
+)
+
+// Lock 代表 SELECT 语句中的行锁
+type Lock struct {
+	Strength LockStrength
+	Wait     LockWait
+}
+
+// IndexHintType 代表索引提示的类型
+type IndexHintType int
+
+const (
+	// IndexHintUse 即 USE INDEX，优化器只会在这些索引中选择
+	IndexHintUse IndexHintType = iota
+	// IndexHintForce 即 FORCE INDEX，除非无法使用这些索引，否则不会全表扫描
+	IndexHintForce
+	// IndexHintIgnore 即 IGNORE INDEX，优化器不会使用这些索引
+	IndexHintIgnore
+)
+
+func (i IndexHintType) String() string {
+	switch i {
+	case IndexHintForce:
+		return "FORCE INDEX"
+	case IndexHintIgnore:
+		return "IGNORE INDEX"
+	default:
+		return "USE INDEX"
 	}
+}
+
+// IndexHint 代表索引提示，Indexes 是索引的名字
+type IndexHint struct {
+	Type    IndexHintType
+	Indexes []string
+}
+
+// Writer 是 Dialect 生成 SQL 片段时所使用的抽象
+type Writer interface {
+	WriteString(val string)
+	// WriteArg 写入一个参数的占位符，并且记录下该参数
+	WriteArg(arg any)
+}
+
+var (
+	MySQL      Dialect = mysqlDialect{}
+	SQLite     Dialect = sqlite3Dialect{}
+	PostgreSQL Dialect = postgresDialect{}
 )
 
+// standardSQL 提供了 SQL 标准的默认实现
+// 其余方言可以通过组合它来减少重复代码
+type standardSQL struct{}
+
+func (standardSQL) Placeholder(int) string {
+	return "?"
+}
+
+func (standardSQL) BuildLimitOffset(w Writer, limit, offset int) {
+	if limit > 0 {
+		w.WriteString(" LIMIT ")
+		w.WriteArg(limit)
+	}
+	if offset > 0 {
+		w.WriteString(" OFFSET ")
+		w.WriteArg(offset)
+	}
+}
+
+func (standardSQL) BuildInsertSuffix(Writer, InsertMode) {}
+
+func (standardSQL) SupportLastInsertID() bool {
+	return true
+}
+
+// FirstInsertID 在 MySQL 里面，批量插入的时候 LastInsertId 返回的是第一行的自增主键
+func (standardSQL) FirstInsertID(lastInsertID int64, _ int64) int64 {
+	return lastInsertID
+}
+
+// BuildLock MySQL 8.0 和 PostgreSQL 都支持 FOR UPDATE, FOR SHARE, NOWAIT 和 SKIP LOCKED
+func (standardSQL) BuildLock(w Writer, lock Lock) error {
+	if lock.Strength == LockNone {
+		return nil
+	}
+	w.WriteString(" ")
+	w.WriteString(lock.Strength.String())
+	switch lock.Wait {
+	case LockNoWait:
+		w.WriteString(" NOWAIT")
+	case LockSkipLocked:
+		w.WriteString(" SKIP LOCKED")
+	}
+	return nil
+}
+
+// SupportNullsOrder PostgreSQL 和 SQLite 3.30.0 之后都支持
+func (standardSQL) SupportNullsOrder() bool {
+	return true
+}
+
+// SupportJoinMutation PostgreSQL 使用的是 UPDATE ... FROM 和 DELETE ... USING，
+// 而 SQLite 只支持 UPDATE ... FROM，所以默认不支持
+func (standardSQL) SupportJoinMutation() bool {
+	return false
+}
+
+// SupportDeleteOrderLimit SQLite 需要在编译的时候开启 SQLITE_ENABLE_UPDATE_DELETE_LIMIT，
+// 所以默认不支持
+func (standardSQL) SupportDeleteOrderLimit() bool {
+	return false
+}
+
+// SupportJoin PostgreSQL 和 SQLite 3.39.0 之后都支持 FULL OUTER JOIN，
+// 而 STRAIGHT_JOIN 是 MySQL 特有的
+func (standardSQL) SupportJoin(typ string) bool {
+	return typ != JoinStraight
+}
+
+func (standardSQL) Func(name string) FuncTemplate {
+	return FuncTemplate{Prefix: name + "(", Sep: ",", Suffix: ")"}
+}
+
+// buildReturning 是支持 RETURNING 子句的方言的通用实现
+func buildReturning(d Dialect, w Writer, columns []string) {
+	w.WriteString(" RETURNING ")
+	for i, c := range columns {
+		if i > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString(d.Quote(c))
+	}
+}
+
+// buildOnConflict 是使用 ON CONFLICT 语法的方言的通用实现
+func buildOnConflict(d Dialect, w Writer, conflictColumns []string) {
+	w.WriteString(" ON CONFLICT")
+	if len(conflictColumns) > 0 {
+		w.WriteString("(")
+		for i, c := range conflictColumns {
+			if i > 0 {
+				w.WriteString(",")
+			}
+			w.WriteString(d.Quote(c))
+		}
+		w.WriteString(")")
+	}
+	w.WriteString(" DO UPDATE SET ")
+}
+
+type mysqlDialect struct {
+	standardSQL
+}
+
+func (mysqlDialect) Name() string {
+	return "MySQL"
+}
+
+func (mysqlDialect) Quote(name string) string {
+	return "`" + name + "`"
+}
+
+// BuildLimitOffset 在 MySQL 里面，OFFSET 不能单独使用，
+// 所以只有 offset 的时候，使用官方文档推荐的最大值作为 LIMIT
+func (m mysqlDialect) BuildLimitOffset(w Writer, limit, offset int) {
+	if limit <= 0 && offset > 0 {
+		w.WriteString(" LIMIT 18446744073709551615 OFFSET ")
+		w.WriteArg(offset)
+		return
+	}
+	m.standardSQL.BuildLimitOffset(w, limit, offset)
+}
+
+func (m mysqlDialect) BuildReturning(Writer, []string) error {
+	return errs.NewUnsupportedClauseError(m.Name(), "RETURNING")
+}
+
+// BuildUpsert MySQL 会根据所有的唯一索引来判断冲突，所以忽略 conflictColumns
+func (mysqlDialect) BuildUpsert(w Writer, _ []string) error {
+	w.WriteString(" ON DUPLICATE KEY UPDATE ")
+	return nil
+}
+
+func (m mysqlDialect) InsertedValue(column string) string {
+	return "VALUES(" + m.Quote(column) + ")"
+}
+
+func (mysqlDialect) BuildInsert(w Writer, mode InsertMode) error {
+	switch mode {
+	case InsertModeIgnore:
+		w.WriteString("INSERT IGNORE INTO ")
+	case InsertModeReplace:
+		w.WriteString("REPLACE INTO ")
+	default:
+		w.WriteString("INSERT INTO ")
+	}
+	return nil
+}
+
+// Func 在 MySQL 里面，LENGTH 返回的是字节数，CHAR_LENGTH 才是字符数
+func (m mysqlDialect) Func(name string) FuncTemplate {
+	if name == FuncLength {
+		return m.standardSQL.Func("CHAR_LENGTH")
+	}
+	return m.standardSQL.Func(name)
+}
+
+func (mysqlDialect) SupportNullsOrder() bool {
+	return false
+}
+
+func (mysqlDialect) SupportJoinMutation() bool {
+	return true
+}
+
+func (mysqlDialect) SupportDeleteOrderLimit() bool {
+	return true
+}
+
+func (m mysqlDialect) BuildIndexHint(w Writer, hint IndexHint) error {
+	w.WriteString(" ")
+	w.WriteString(hint.Type.String())
+	w.WriteString(" (")
+	for i, idx := range hint.Indexes {
+		if i > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString(m.Quote(idx))
+	}
+	w.WriteString(")")
+	return nil
+}
+
+// SupportJoin MySQL 不支持 FULL OUTER JOIN，一般使用 LEFT JOIN 和 RIGHT JOIN 的 UNION 来代替
+func (mysqlDialect) SupportJoin(typ string) bool {
+	return typ != JoinFullOuter
+}
+
+type sqlite3Dialect struct {
+	standardSQL
+}
+
+func (sqlite3Dialect) Name() string {
+	return "SQLite"
+}
+
+func (sqlite3Dialect) Quote(name string) string {
+	return "`" + name + "`"
+}
+
+// BuildLimitOffset 在 SQLite 里面，OFFSET 同样不能单独使用，
+// LIMIT -1 代表没有上限
+func (s sqlite3Dialect) BuildLimitOffset(w Writer, limit, offset int) {
+	if limit <= 0 && offset > 0 {
+		w.WriteString(" LIMIT -1 OFFSET ")
+		w.WriteArg(offset)
+		return
+	}
+	s.standardSQL.BuildLimitOffset(w, limit, offset)
+}
+
+// BuildReturning SQLite 从 3.35.0 开始支持 RETURNING
+func (s sqlite3Dialect) BuildReturning(w Writer, columns []string) error {
+	buildReturning(s, w, columns)
+	return nil
+}
+
+// BuildUpsert SQLite 从 3.35.0 开始，允许最后一个 ON CONFLICT 不指定冲突列
+func (s sqlite3Dialect) BuildUpsert(w Writer, conflictColumns []string) error {
+	buildOnConflict(s, w, conflictColumns)
+	return nil
+}
+
+func (s sqlite3Dialect) InsertedValue(column string) string {
+	return "excluded." + s.Quote(column)
+}
+
+func (sqlite3Dialect) BuildInsert(w Writer, mode InsertMode) error {
+	switch mode {
+	case InsertModeIgnore:
+		w.WriteString("INSERT OR IGNORE INTO ")
+	case InsertModeReplace:
+		w.WriteString("INSERT OR REPLACE INTO ")
+	default:
+		w.WriteString("INSERT INTO ")
+	}
+	return nil
+}
+
+// FirstInsertID 在 SQLite 里面，LastInsertId 返回的是最后一行的 rowid
+func (sqlite3Dialect) FirstInsertID(lastInsertID int64, n int64) int64 {
+	return lastInsertID - n + 1
+}
+
+// BuildLock SQLite 锁住的是整个数据库，不支持行锁
+func (s sqlite3Dialect) BuildLock(_ Writer, lock Lock) error {
+	if lock.Strength == LockNone {
+		return nil
+	}
+	return errs.NewUnsupportedClauseError(s.Name(), lock.Strength.String())
+}
+
+// BuildIndexHint SQLite 只有 INDEXED BY，并且只能指定一个索引，所以不支持
+func (s sqlite3Dialect) BuildIndexHint(_ Writer, hint IndexHint) error {
+	return errs.NewUnsupportedClauseError(s.Name(), hint.Type.String())
+}
+
+// Func SQLite 使用 || 来拼接字符串，并且没有 NOW 和 SUBSTRING
+func (s sqlite3Dialect) Func(name string) FuncTemplate {
+	switch name {
+	case FuncConcat:
+		return FuncTemplate{Prefix: "(", Sep: "||", Suffix: ")"}
+	case FuncNow:
+		return FuncTemplate{Prefix: "DATETIME('now'", Suffix: ")"}
+	case FuncSubstring:
+		return s.standardSQL.Func("SUBSTR")
+	default:
+		return s.standardSQL.Func(name)
+	}
+}
+
+type postgresDialect struct {
+	standardSQL
+}
+
+func (postgresDialect) Name() string {
+	return "PostgreSQL"
+}
+
+func (postgresDialect) Quote(name string) string {
+	return `"` + name + `"`
+}
+
+// Placeholder 在 PostgreSQL 里面，占位符是 $1, $2...
+func (postgresDialect) Placeholder(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+func (p postgresDialect) BuildReturning(w Writer, columns []string) error {
+	buildReturning(p, w, columns)
+	return nil
+}
+
+// BuildUpsert PostgreSQL 的 ON CONFLICT DO UPDATE 必须指定冲突列
+func (p postgresDialect) BuildUpsert(w Writer, conflictColumns []string) error {
+	if len(conflictColumns) == 0 {
+		return errs.ErrMissingConflictColumns
+	}
+	buildOnConflict(p, w, conflictColumns)
+	return nil
+}
+
+func (p postgresDialect) InsertedValue(column string) string {
+	return "excluded." + p.Quote(column)
+}
+
+// BuildInsert PostgreSQL 没有 REPLACE 语义，
+// 而忽略冲突是通过末尾的 ON CONFLICT DO NOTHING 来实现的
+func (p postgresDialect) BuildInsert(w Writer, mode InsertMode) error {
+	if mode == InsertModeReplace {
+		return errs.NewUnsupportedClauseError(p.Name(), "REPLACE")
+	}
+	w.WriteString("INSERT INTO ")
+	return nil
+}
+
+func (postgresDialect) BuildInsertSuffix(w Writer, mode InsertMode) {
+	if mode == InsertModeIgnore {
+		w.WriteString(" ON CONFLICT DO NOTHING")
+	}
+}
+
+// SupportLastInsertID PostgreSQL 的驱动都不支持 LastInsertId
+func (postgresDialect) SupportLastInsertID() bool {
+	return false
+}
+
+// Func PostgreSQL 没有 DATE 函数，需要使用 CAST
+func (p postgresDialect) Func(name string) FuncTemplate {
+	if name == FuncDate {
+		return FuncTemplate{Prefix: "CAST(", Suffix: " AS DATE)"}
+	}
+	return p.standardSQL.Func(name)
+}
+
+// BuildIndexHint PostgreSQL 不支持索引提示，需要借助 pg_hint_plan 插件，
+// 使用 Hints 来指定优化器提示
+func (p postgresDialect) BuildIndexHint(_ Writer, hint IndexHint) error {
+	return errs.NewUnsupportedClauseError(p.Name(), hint.Type.String())
+}
+
 func Of(driver string) (Dialect, error) {
 	switch driver {
 	case "sqlite3":
 		return SQLite, nil
 	case "mysql":
 		return MySQL, nil
+	case "postgres", "pgx":
+		return PostgreSQL, nil
 	default:
-		return Dialect{}, errs.NewUnsupportedDriverError(driver)
+		return nil, errs.NewUnsupportedDriverError(driver)
 	}
 }
@@ -15,6 +15,7 @@
 package dialect
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ecodeclub/eorm/internal/errs"
@@ -38,6 +39,16 @@ func TestOf(t *testing.T) {
 			driver:      "sqlite3",
 			wantDialect: SQLite,
 		},
+		{
+			name:        "postgres",
+			driver:      "postgres",
+			wantDialect: PostgreSQL,
+		},
+		{
+			name:        "pgx",
+			driver:      "pgx",
+			wantDialect: PostgreSQL,
+		},
 		{
 			name:    "unsupported",
 			driver:  "abc",
@@ -56,3 +67,415 @@ func TestOf(t *testing.T) {
 		})
 	}
 }
+
+func TestDialect_BuildLimitOffset(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dialect  Dialect
+		limit    int
+		offset   int
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "mysql none",
+			dialect: MySQL,
+		},
+		{
+			name:     "mysql limit",
+			dialect:  MySQL,
+			limit:    10,
+			wantSQL:  " LIMIT ?",
+			wantArgs: []any{10},
+		},
+		{
+			name:     "mysql offset",
+			dialect:  MySQL,
+			offset:   20,
+			wantSQL:  " LIMIT 18446744073709551615 OFFSET ?",
+			wantArgs: []any{20},
+		},
+		{
+			name:     "mysql limit offset",
+			dialect:  MySQL,
+			limit:    10,
+			offset:   20,
+			wantSQL:  " LIMIT ? OFFSET ?",
+			wantArgs: []any{10, 20},
+		},
+		{
+			name:     "postgres offset",
+			dialect:  PostgreSQL,
+			offset:   20,
+			wantSQL:  " OFFSET ?",
+			wantArgs: []any{20},
+		},
+		{
+			name:     "sqlite offset",
+			dialect:  SQLite,
+			offset:   20,
+			wantSQL:  " LIMIT -1 OFFSET ?",
+			wantArgs: []any{20},
+		},
+		{
+			name:     "sqlite limit offset",
+			dialect:  SQLite,
+			limit:    10,
+			offset:   20,
+			wantSQL:  " LIMIT ? OFFSET ?",
+			wantArgs: []any{10, 20},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &testWriter{}
+			tc.dialect.BuildLimitOffset(w, tc.limit, tc.offset)
+			assert.Equal(t, tc.wantSQL, w.sb.String())
+			assert.Equal(t, tc.wantArgs, w.args)
+		})
+	}
+}
+
+func TestDialect_BuildReturning(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		columns []string
+		wantSQL string
+		wantErr error
+	}{
+		{
+			name:    "mysql",
+			dialect: MySQL,
+			columns: []string{"id"},
+			wantErr: errs.NewUnsupportedClauseError("MySQL", "RETURNING"),
+		},
+		{
+			name:    "sqlite",
+			dialect: SQLite,
+			columns: []string{"id", "age"},
+			wantSQL: " RETURNING `id`,`age`",
+		},
+		{
+			name:    "postgres",
+			dialect: PostgreSQL,
+			columns: []string{"id", "age"},
+			wantSQL: ` RETURNING "id","age"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &testWriter{}
+			err := tc.dialect.BuildReturning(w, tc.columns)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantSQL, w.sb.String())
+		})
+	}
+}
+
+func TestDialect_BuildUpsert(t *testing.T) {
+	testCases := []struct {
+		name            string
+		dialect         Dialect
+		conflictColumns []string
+		wantSQL         string
+		wantErr         error
+	}{
+		{
+			name:            "mysql",
+			dialect:         MySQL,
+			conflictColumns: []string{"id"},
+			wantSQL:         " ON DUPLICATE KEY UPDATE ",
+		},
+		{
+			name:    "sqlite without conflict columns",
+			dialect: SQLite,
+			wantSQL: " ON CONFLICT DO UPDATE SET ",
+		},
+		{
+			name:            "sqlite",
+			dialect:         SQLite,
+			conflictColumns: []string{"id", "name"},
+			wantSQL:         " ON CONFLICT(`id`,`name`) DO UPDATE SET ",
+		},
+		{
+			name:    "postgres without conflict columns",
+			dialect: PostgreSQL,
+			wantErr: errs.ErrMissingConflictColumns,
+		},
+		{
+			name:            "postgres",
+			dialect:         PostgreSQL,
+			conflictColumns: []string{"id"},
+			wantSQL:         ` ON CONFLICT("id") DO UPDATE SET `,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &testWriter{}
+			err := tc.dialect.BuildUpsert(w, tc.conflictColumns)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantSQL, w.sb.String())
+		})
+	}
+}
+
+func TestDialect_BuildInsert(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		mode    InsertMode
+		wantSQL string
+		wantErr error
+	}{
+		{
+			name:    "mysql default",
+			dialect: MySQL,
+			wantSQL: "INSERT INTO ",
+		},
+		{
+			name:    "mysql ignore",
+			dialect: MySQL,
+			mode:    InsertModeIgnore,
+			wantSQL: "INSERT IGNORE INTO ",
+		},
+		{
+			name:    "mysql replace",
+			dialect: MySQL,
+			mode:    InsertModeReplace,
+			wantSQL: "REPLACE INTO ",
+		},
+		{
+			name:    "sqlite ignore",
+			dialect: SQLite,
+			mode:    InsertModeIgnore,
+			wantSQL: "INSERT OR IGNORE INTO ",
+		},
+		{
+			name:    "sqlite replace",
+			dialect: SQLite,
+			mode:    InsertModeReplace,
+			wantSQL: "INSERT OR REPLACE INTO ",
+		},
+		{
+			name:    "postgres ignore",
+			dialect: PostgreSQL,
+			mode:    InsertModeIgnore,
+			wantSQL: "INSERT INTO ",
+		},
+		{
+			name:    "postgres replace",
+			dialect: PostgreSQL,
+			mode:    InsertModeReplace,
+			wantErr: errs.NewUnsupportedClauseError("PostgreSQL", "REPLACE"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &testWriter{}
+			err := tc.dialect.BuildInsert(w, tc.mode)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantSQL, w.sb.String())
+		})
+	}
+}
+
+func TestDialect_BuildLock(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		lock    Lock
+		wantSQL string
+		wantErr error
+	}{
+		{
+			name:    "mysql none",
+			dialect: MySQL,
+		},
+		{
+			name:    "mysql for update",
+			dialect: MySQL,
+			lock:    Lock{Strength: LockForUpdate},
+			wantSQL: " FOR UPDATE",
+		},
+		{
+			name:    "mysql for share nowait",
+			dialect: MySQL,
+			lock:    Lock{Strength: LockForShare, Wait: LockNoWait},
+			wantSQL: " FOR SHARE NOWAIT",
+		},
+		{
+			name:    "postgres for update skip locked",
+			dialect: PostgreSQL,
+			lock:    Lock{Strength: LockForUpdate, Wait: LockSkipLocked},
+			wantSQL: " FOR UPDATE SKIP LOCKED",
+		},
+		{
+			name:    "sqlite none",
+			dialect: SQLite,
+		},
+		{
+			name:    "sqlite for update",
+			dialect: SQLite,
+			lock:    Lock{Strength: LockForUpdate},
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "FOR UPDATE"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &testWriter{}
+			err := tc.dialect.BuildLock(w, tc.lock)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantSQL, w.sb.String())
+		})
+	}
+}
+
+func TestDialect_BuildIndexHint(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		hint    IndexHint
+		wantSQL string
+		wantErr error
+	}{
+		{
+			name:    "mysql force index",
+			dialect: MySQL,
+			hint:    IndexHint{Type: IndexHintForce, Indexes: []string{"idx_a", "idx_b"}},
+			wantSQL: " FORCE INDEX (`idx_a`,`idx_b`)",
+		},
+		{
+			name:    "mysql ignore index",
+			dialect: MySQL,
+			hint:    IndexHint{Type: IndexHintIgnore, Indexes: []string{"idx_a"}},
+			wantSQL: " IGNORE INDEX (`idx_a`)",
+		},
+		{
+			name:    "sqlite",
+			dialect: SQLite,
+			hint:    IndexHint{Type: IndexHintUse, Indexes: []string{"idx_a"}},
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "USE INDEX"),
+		},
+		{
+			name:    "postgres",
+			dialect: PostgreSQL,
+			hint:    IndexHint{Type: IndexHintForce, Indexes: []string{"idx_a"}},
+			wantErr: errs.NewUnsupportedClauseError("PostgreSQL", "FORCE INDEX"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &testWriter{}
+			err := tc.dialect.BuildIndexHint(w, tc.hint)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantSQL, w.sb.String())
+		})
+	}
+}
+
+func TestDialect_Func(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		fn      string
+		want    FuncTemplate
+	}{
+		{
+			name:    "standard",
+			dialect: MySQL,
+			fn:      "COALESCE",
+			want:    FuncTemplate{Prefix: "COALESCE(", Sep: ",", Suffix: ")"},
+		},
+		{
+			name:    "mysql length",
+			dialect: MySQL,
+			fn:      FuncLength,
+			want:    FuncTemplate{Prefix: "CHAR_LENGTH(", Sep: ",", Suffix: ")"},
+		},
+		{
+			name:    "sqlite concat",
+			dialect: SQLite,
+			fn:      FuncConcat,
+			want:    FuncTemplate{Prefix: "(", Sep: "||", Suffix: ")"},
+		},
+		{
+			name:    "sqlite now",
+			dialect: SQLite,
+			fn:      FuncNow,
+			want:    FuncTemplate{Prefix: "DATETIME('now'", Suffix: ")"},
+		},
+		{
+			name:    "sqlite substring",
+			dialect: SQLite,
+			fn:      FuncSubstring,
+			want:    FuncTemplate{Prefix: "SUBSTR(", Sep: ",", Suffix: ")"},
+		},
+		{
+			name:    "postgres date",
+			dialect: PostgreSQL,
+			fn:      FuncDate,
+			want:    FuncTemplate{Prefix: "CAST(", Suffix: " AS DATE)"},
+		},
+		{
+			name:    "postgres concat",
+			dialect: PostgreSQL,
+			fn:      FuncConcat,
+			want:    FuncTemplate{Prefix: "CONCAT(", Sep: ",", Suffix: ")"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.dialect.Func(tc.fn))
+		})
+	}
+}
+
+func TestDialect_FirstInsertID(t *testing.T) {
+	assert.True(t, MySQL.SupportLastInsertID())
+	assert.Equal(t, int64(10), MySQL.FirstInsertID(10, 3))
+	assert.True(t, SQLite.SupportLastInsertID())
+	assert.Equal(t, int64(8), SQLite.FirstInsertID(10, 3))
+	assert.False(t, PostgreSQL.SupportLastInsertID())
+}
+
+func TestPostgreSQL_Placeholder(t *testing.T) {
+	assert.Equal(t, "$1", PostgreSQL.Placeholder(1))
+	assert.Equal(t, "$12", PostgreSQL.Placeholder(12))
+	assert.Equal(t, "?", MySQL.Placeholder(12))
+}
+
+type testWriter struct {
+	sb   strings.Builder
+	args []any
+}
+
+func (w *testWriter) WriteString(val string) {
+	w.sb.WriteString(val)
+}
+
+func (w *testWriter) WriteArg(arg any) {
+	w.args = append(w.args, arg)
+	w.sb.WriteString("?")
+}
@@ -27,8 +27,8 @@ var (
 	// 一般是查询的列多于结构体的列
 	ErrTooManyColumns = errors.New("eorm: 过多列")
 
-	// ErrCombinationIsNotStruct 不支持的组合类型，eorm 只支持结构体组合
-	ErrCombinationIsNotStruct            = errors.New("eorm: 不支持的组合类型，eorm 只支持结构体组合")
+	// ErrCombinationIsNotStruct 不支持的组合类型，eorm 只支持结构体组合和结构体指针组合
+	ErrCombinationIsNotStruct            = errors.New("eorm: 不支持的组合类型，eorm 只支持结构体组合和结构体指针组合")
 	ErrMissingShardingKey                = errors.New("eorm: sharding key 未设置")
 	ErrOnlyResultOneQuery                = errors.New("eorm: 只能生成一个 SQL")
 	ErrUnsupportedTooComplexQuery        = errors.New("eorm: 暂未支持太复杂的查询")
@@ -37,8 +37,27 @@ var (
 	ErrNotCompleteTxBeginner             = errors.New("eorm: 未实现 TxBeginner 接口")
 	ErrInsertShardingKeyNotFound         = errors.New("eorm: insert语句中未包含sharding key")
 	ErrInsertFindingDst                  = errors.New("eorm: 一行数据只能插入一个表")
+	ErrLockWaitWithoutLock               = errors.New("eorm: NOWAIT 和 SKIP LOCKED 必须和 FOR UPDATE 或者 FOR SHARE 一起使用")
+	ErrCTEWithoutName                    = errors.New("eorm: CTE 必须指定名字")
+	ErrCaseWithoutWhen                   = errors.New("eorm: CASE 表达式至少需要一个 WHEN")
+	ErrIndexHintWithoutIndex             = errors.New("eorm: 索引提示必须指定索引")
+	ErrIndexHintOnlySingleTable          = errors.New("eorm: 索引提示只能用于单表查询")
+	ErrDeleteJoinWithOrderLimit          = errors.New("eorm: 多表 DELETE 不支持 ORDER BY 和 LIMIT")
+	ErrSoftDeleteOuterJoinUsing          = errors.New("eorm: 软删除不支持使用 USING 的外连接，请使用 ON 或者 Unscoped")
+	ErrSoftDeleteFullOuterJoin           = errors.New("eorm: 软删除不支持 FULL OUTER JOIN，请使用 Unscoped 并且自行过滤已经删除的数据")
+	ErrSoftDeleteJoin                    = errors.New("eorm: 软删除不支持多表 DELETE，需要物理删除的时候请使用 HardDelete")
 	ErrUnsupportedAssignment             = errors.New("eorm: 不支持的 assignment")
+	ErrUpdateJoinUnqualifiedColumn       = errors.New("eorm: 多表更新的时候必须使用 Table.C(...).Assign(...) 指定要更新的列属于哪个表")
 	ErrUnsupportedDistributedTransaction = errors.New("eorm: 不支持的分布式事务类型")
+	ErrMissingConflictColumns            = errors.New("eorm: upsert 未指定冲突列")
+	ErrSinglePrimaryKeyOnly              = errors.New("eorm: 回填主键要求模型有且只有一个主键")
+	ErrCompoundPartOrderLimit            = errors.New("eorm: 组合查询中的 SELECT 不能使用 ORDER BY、LIMIT、OFFSET 或者行锁，请在组合查询上使用 OrderBy、Limit 和 Offset")
+	ErrInvalidBatchSize                  = errors.New("eorm: 分批插入的时候每一批至少要有一行，请检查 Batch 和 BatchByArgs 的参数")
+	ErrBatchInTxUnsupported              = errors.New("eorm: 当前的 Session 不支持 BatchInTx")
+	ErrFillPKMixedExplicit               = errors.New("eorm: 回填主键的时候，不能同时插入指定了主键和没有指定主键的行")
+	ErrFillPKWithConflict                = errors.New("eorm: 回填主键不能和 INSERT IGNORE、REPLACE 或者 upsert 一起使用，因为无法确定每一行的主键")
+	ErrInsertModeWithUpsert              = errors.New("eorm: INSERT IGNORE 和 REPLACE 不能和 upsert 一起使用")
+	ErrNamingStrategyUnsupported         = errors.New("eorm: 当前的 MetaRegistry 不支持设置命名策略")
 )
 
 func NewErrDBNotEqual(oldDB, tgtDB string) error {
@@ -65,6 +84,11 @@ func NewFieldConflictError(field string) error {
 	return fmt.Errorf("eorm: `%s`列冲突", field)
 }
 
+// NewInvalidTagContentError eorm 标签的内容不合法，例如 column= 后面没有列名
+func NewInvalidTagContentError(field string) error {
+	return fmt.Errorf("eorm: 字段 %s 的 eorm 标签不合法", field)
+}
+
 // NewInvalidFieldError 返回代表未知字段的错误。
 // 通常来说，是字段名没写对
 // 注意区分 NewInvalidColumnError
@@ -83,11 +107,26 @@ func NewValueNotSetError() error {
 	return errValueNotSet
 }
 
+// NewUsingColumnConflictError USING 中的字段在 JOIN 两边对应的列名不一致
+func NewUsingColumnConflictError(field string) error {
+	return fmt.Errorf("eorm: USING 中的字段 %s 在 JOIN 两边对应的列名不一致", field)
+}
+
 // NewUnsupportedDriverError 不支持驱动类型
 func NewUnsupportedDriverError(driver string) error {
 	return fmt.Errorf("eorm: 不支持driver类型 %s", driver)
 }
 
+// NewInvalidHintError 优化器提示中包含 */，会提前结束注释
+func NewInvalidHintError(hint string) error {
+	return fmt.Errorf("eorm: 优化器提示 %s 不能包含 */", hint)
+}
+
+// NewUnsupportedClauseError 方言不支持的子句或者特性
+func NewUnsupportedClauseError(dialect string, clause string) error {
+	return fmt.Errorf("eorm: %s 不支持 %s", dialect, clause)
+}
+
 // NewUnsupportedTableReferenceError 不支持的TableReference类型
 func NewUnsupportedTableReferenceError(table any) error {
 	return fmt.Errorf("eorm: 不支持的TableReference类型 %v", table)
@@ -101,6 +140,11 @@ func NewMustSpecifyColumnsError() error {
 	return fmt.Errorf("eorm: 复合查询如 JOIN 查询、子查询必须指定要查找的列，即指定 SELECT xxx 部分")
 }
 
+// NewColumnCountMismatchError 两边的列数不一致，例如 INSERT SELECT 中插入的列数和查询的列数不一致
+func NewColumnCountMismatchError(expect int, actual int) error {
+	return fmt.Errorf("eorm: 列数不一致，预期 %d，实际 %d", expect, actual)
+}
+
 func NewUnsupportedOperatorError(op string) error {
 	return fmt.Errorf("eorm: 不支持的 operator %v", op)
 }
@@ -15,16 +15,15 @@
 package model
 
 import (
+	"database/sql"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ecodeclub/eorm/internal/sharding"
 
 	"github.com/ecodeclub/eorm/internal/errs"
-
-	// nolint
-	"unicode"
 )
 
 // TableMeta represents data model, or a table
@@ -36,6 +35,8 @@ type TableMeta struct {
 	// ColumnMap 是列名到列元数据的映射
 	ColumnMap map[string]*ColumnMeta
 	Typ       reflect.Type
+	// SoftDeleteColumn 是标记了 softDelete 的列，为 nil 说明不支持软删除
+	SoftDeleteColumn *ColumnMeta
 
 	ShardingAlgorithm sharding.Algorithm
 }
@@ -59,11 +60,111 @@ type ColumnMeta struct {
 	Offset uintptr
 	// FieldIndexes 用于表达从最外层结构体找到当前ColumnMeta对应的Field所需要的索引集
 	FieldIndexes []int
+	// Embedded 是从最外层结构体到当前字段所经过的指针组合，按照从外到内的顺序排列。
+	// 在这种情况下，Offset 是相对于最内层的指针所指向的结构体的偏移量，
+	// 因为偏移量无法跨越指针
+	Embedded []EmbeddedPtr
+	// AutoCreateTime 插入的时候，如果字段是零值，就自动填充当前时间
+	AutoCreateTime bool
+	// AutoUpdateTime 插入和更新的时候自动填充当前时间
+	AutoUpdateTime bool
+	// SoftDelete 表示该列用于软删除
+	SoftDelete bool
+	// TimeUnit 是自动填充的时间的精度，只对整数类型的字段生效
+	TimeUnit TimeUnit
+}
+
+// TimeUnit 是整数类型的时间字段的精度
+type TimeUnit string
+
+const (
+	// TimeUnitSecond 秒，即 Unix 时间戳，标签 autoCreateTime 和 autoUpdateTime 默认使用秒
+	TimeUnitSecond TimeUnit = ""
+	// TimeUnitMilli 毫秒，对应标签 autoCreateTime:milli 和 autoUpdateTime:milli
+	TimeUnitMilli TimeUnit = "milli"
+)
+
+// TimeValue 将 now 转化为字段类型的值，用于自动填充时间
+func (c *ColumnMeta) TimeValue(now time.Time) any {
+	switch c.Typ {
+	case timeType:
+		return now
+	case timePtrType:
+		return &now
+	case nullTimeType:
+		return sql.NullTime{Time: now, Valid: true}
+	}
+	ts := now.Unix()
+	if c.TimeUnit == TimeUnitMilli {
+		ts = now.UnixMilli()
+	}
+	return reflect.ValueOf(ts).Convert(c.Typ).Interface()
+}
+
+// DeletedValue 返回软删除的时候列的值，
+// bool 类型的列是 true，其余的是删除的时间
+func (c *ColumnMeta) DeletedValue(now time.Time) any {
+	if c.Typ.Kind() == reflect.Bool {
+		return true
+	}
+	return c.TimeValue(now)
+}
+
+// NotDeletedValue 返回没有被软删除的时候列的值，nil 代表 NULL
+func (c *ColumnMeta) NotDeletedValue() any {
+	if c.Typ.Kind() == reflect.Ptr || c.Typ == nullTimeType {
+		return nil
+	}
+	return reflect.Zero(c.Typ).Interface()
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	timePtrType  = reflect.TypeOf(&time.Time{})
+	nullTimeType = reflect.TypeOf(sql.NullTime{})
+)
+
+// isSoftDeleteType 软删除的字段可以是 bool，可以为 NULL 的时间或者整数
+func isSoftDeleteType(typ reflect.Type, unit TimeUnit) bool {
+	if typ.Kind() == reflect.Bool || typ == timePtrType || typ == nullTimeType {
+		return unit == TimeUnitSecond
+	}
+	return typ != timeType && isAutoTimeType(typ, unit)
+}
+
+// isAutoTimeType 自动填充时间的字段只能是 time.Time 或者整数
+func isAutoTimeType(typ reflect.Type, unit TimeUnit) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return (typ == timeType || typ == timePtrType) && unit == TimeUnitSecond
+}
+
+// EmbeddedPtr 代表一个指针组合，例如结构体 A 里面组合了 *B
+type EmbeddedPtr struct {
+	// Offset 是指针字段相对于它所在的结构体的偏移量
+	Offset uintptr
+	// Typ 是指针所指向的结构体的类型
+	Typ reflect.Type
 }
 
 // TableMetaOption represents options of TableMeta, this options will cover default cover.
 type TableMetaOption func(meta *TableMeta)
 
+// WithTableName 指定表名，优先级高于 TableName 方法
+func WithTableName(name string) TableMetaOption {
+	return func(meta *TableMeta) {
+		meta.TableName = name
+	}
+}
+
+// TableNamer 模型可以实现该接口来指定表名，
+// 没有实现的时候，表名是结构体名字的下划线形式
+type TableNamer interface {
+	TableName() string
+}
+
 func WithTableShardingAlgorithm(algorithm sharding.Algorithm) TableMetaOption {
 	return func(meta *TableMeta) {
 		meta.ShardingAlgorithm = algorithm
@@ -80,13 +181,40 @@ func NewMetaRegistry() MetaRegistry {
 	return &tagMetaRegistry{}
 }
 
+// MetaRegistryOption 用于配置 MetaRegistry
+type MetaRegistryOption func(r *tagMetaRegistry)
+
+// WithNamingStrategy 指定命名策略，默认是 Underscore
+func WithNamingStrategy(naming NamingStrategy) MetaRegistryOption {
+	return func(r *tagMetaRegistry) {
+		r.naming = naming
+	}
+}
+
 // tagMetaRegistry is the default implementation based on tag eorm
 type tagMetaRegistry struct {
-	metas sync.Map
+	metas  sync.Map
+	naming NamingStrategy
 }
 
-func NewTagMetaRegistry() MetaRegistry {
-	return &tagMetaRegistry{}
+func NewTagMetaRegistry(opts ...MetaRegistryOption) MetaRegistry {
+	r := &tagMetaRegistry{}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// SetNamingStrategy 修改命名策略，只会影响之后注册的模型
+func (t *tagMetaRegistry) SetNamingStrategy(naming NamingStrategy) {
+	t.naming = naming
+}
+
+func (t *tagMetaRegistry) namingStrategy() NamingStrategy {
+	if t.naming == nil {
+		return Underscore()
+	}
+	return t.naming
 }
 
 // Get the metadata for each column of the data table,
@@ -110,21 +238,39 @@ func (t *tagMetaRegistry) Register(table interface{}, opts ...TableMetaOption) (
 	columnMetas := make([]*ColumnMeta, 0, lens)
 	fieldMap := make(map[string]*ColumnMeta, lens)
 	columnMap := make(map[string]*ColumnMeta, lens)
-	err := t.parseFields(v, []int{}, &columnMetas, fieldMap, 0)
+	err := t.parseFields(v, []int{}, nil, &columnMetas, fieldMap, 0)
 	if err != nil {
 		return nil, err
 	}
 
+	var softDelete *ColumnMeta
 	for _, columnMeta := range columnMetas {
+		// 通过 column 标签指定列名之后，不同的字段可能会映射到同一列
+		if columnMap[columnMeta.ColumnName] != nil {
+			return nil, errs.NewFieldConflictError(v.Name() + "." + columnMeta.ColumnName)
+		}
 		columnMap[columnMeta.ColumnName] = columnMeta
+		if columnMeta.SoftDelete {
+			// 只能有一个软删除的列
+			if softDelete != nil {
+				return nil, errs.NewInvalidTagContentError(v.Name() + "." + columnMeta.FieldName)
+			}
+			softDelete = columnMeta
+		}
 	}
 
+	tableName := t.namingStrategy().TableName(v.Name())
+	// table 可能是 nil 指针，所以使用一个新的实例来获得表名
+	if tn, ok := reflect.New(v).Interface().(TableNamer); ok {
+		tableName = tn.TableName()
+	}
 	tableMeta := &TableMeta{
-		Columns:   columnMetas,
-		TableName: underscoreName(v.Name()),
-		Typ:       rtype,
-		FieldMap:  fieldMap,
-		ColumnMap: columnMap,
+		Columns:          columnMetas,
+		TableName:        tableName,
+		Typ:              rtype,
+		FieldMap:         fieldMap,
+		ColumnMap:        columnMap,
+		SoftDeleteColumn: softDelete,
 	}
 	for _, o := range opts {
 		o(tableMeta)
@@ -135,22 +281,57 @@ func (t *tagMetaRegistry) Register(table interface{}, opts ...TableMetaOption) (
 
 }
 
-func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int,
+func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int, embedded []EmbeddedPtr,
 	columnMetas *[]*ColumnMeta, fieldMap map[string]*ColumnMeta,
 	pOffset uintptr) error {
 	lens := v.NumField()
 	for i := 0; i < lens; i++ {
 		structField := v.Field(i)
 		tag := structField.Tag.Get("eorm")
-		var isKey, isIgnore bool
+		var isKey, isIgnore, autoCreate, autoUpdate, softDelete bool
+		var unit TimeUnit
+		// 一个列只有一个时间单位，所以多个时间相关的标签必须使用相同的单位
+		var hasUnit, unitConflict bool
+		setUnit := func(tag, prefix string) {
+			u := TimeUnit(strings.TrimPrefix(strings.TrimPrefix(tag, prefix), ":"))
+			if hasUnit && u != unit {
+				unitConflict = true
+			}
+			unit, hasUnit = u, true
+		}
+		columnName := t.namingStrategy().ColumnName(structField.Name)
 		for _, t := range strings.Split(tag, ",") {
-			switch t {
-			case "primary_key":
+			switch {
+			case t == "primary_key":
 				isKey = true
-			case "-":
+			case t == "-":
 				isIgnore = true
+			case strings.HasPrefix(t, "column="):
+				columnName = strings.TrimPrefix(t, "column=")
+				if columnName == "" {
+					return errs.NewInvalidTagContentError(v.Name() + "." + structField.Name)
+				}
+			case t == "autoCreateTime" || strings.HasPrefix(t, "autoCreateTime:"):
+				autoCreate = true
+				setUnit(t, "autoCreateTime")
+			case t == "autoUpdateTime" || strings.HasPrefix(t, "autoUpdateTime:"):
+				autoUpdate = true
+				setUnit(t, "autoUpdateTime")
+			case t == "softDelete" || strings.HasPrefix(t, "softDelete:"):
+				softDelete = true
+				setUnit(t, "softDelete")
 			}
 		}
+		if unitConflict {
+			return errs.NewInvalidTagContentError(v.Name() + "." + structField.Name)
+		}
+		if (autoCreate || autoUpdate) &&
+			((unit != TimeUnitSecond && unit != TimeUnitMilli) || !isAutoTimeType(structField.Type, unit)) {
+			return errs.NewInvalidTagContentError(v.Name() + "." + structField.Name)
+		}
+		if softDelete && ((unit != TimeUnitSecond && unit != TimeUnitMilli) || !isSoftDeleteType(structField.Type, unit)) {
+			return errs.NewInvalidTagContentError(v.Name() + "." + structField.Name)
+		}
 		if isIgnore {
 			// skip the field.
 			continue
@@ -161,13 +342,22 @@ func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int,
 		}
 		// 是组合
 		if structField.Anonymous {
-			// 不支持使用指针的组合
-			if structField.Type.Kind() != reflect.Struct {
+			typ := structField.Type
+			o := structField.Offset + pOffset
+			emb := embedded
+			// 指针组合，内部字段的偏移量从指针指向的结构体重新开始计算
+			if typ.Kind() == reflect.Ptr {
+				typ = typ.Elem()
+				emb = make([]EmbeddedPtr, 0, len(embedded)+1)
+				emb = append(emb, embedded...)
+				emb = append(emb, EmbeddedPtr{Offset: o, Typ: typ})
+				o = 0
+			}
+			if typ.Kind() != reflect.Struct {
 				return errs.ErrCombinationIsNotStruct
 			}
 			// 递归解析
-			o := structField.Offset + pOffset
-			err := t.parseFields(structField.Type, append(fieldIndexes, i), columnMetas, fieldMap, o)
+			err := t.parseFields(typ, append(fieldIndexes, i), emb, columnMetas, fieldMap, o)
 			if err != nil {
 				return err
 			}
@@ -175,12 +365,17 @@ func (t *tagMetaRegistry) parseFields(v reflect.Type, fieldIndexes []int,
 		}
 
 		columnMeta := &ColumnMeta{
-			ColumnName:   underscoreName(structField.Name),
-			FieldName:    structField.Name,
-			Typ:          structField.Type,
-			IsPrimaryKey: isKey,
-			Offset:       structField.Offset + pOffset,
-			FieldIndexes: append(fieldIndexes, i),
+			ColumnName:     columnName,
+			FieldName:      structField.Name,
+			Typ:            structField.Type,
+			IsPrimaryKey:   isKey,
+			Offset:         structField.Offset + pOffset,
+			FieldIndexes:   append(fieldIndexes, i),
+			Embedded:       embedded,
+			AutoCreateTime: autoCreate,
+			AutoUpdateTime: autoUpdate,
+			SoftDelete:     softDelete,
+			TimeUnit:       unit,
 		}
 		*columnMetas = append(*columnMetas, columnMeta)
 		fieldMap[columnMeta.FieldName] = columnMeta
@@ -207,20 +402,3 @@ func IgnoreFieldsOption(fieldNames ...string) TableMetaOption {
 		}
 	}
 }
-
-// underscoreName function mainly converts upper case to lower case and adds an underscore in between
-func underscoreName(tableName string) string {
-	var buf []byte
-	for i, v := range tableName {
-		if unicode.IsUpper(v) {
-			if i != 0 {
-				buf = append(buf, '_')
-			}
-			buf = append(buf, byte(unicode.ToLower(v)))
-		} else {
-			buf = append(buf, byte(v))
-		}
-
-	}
-	return string(buf)
-}
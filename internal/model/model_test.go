@@ -15,13 +15,16 @@
 package model
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ecodeclub/eorm/internal/errs"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTagMetaRegistry(t *testing.T) {
@@ -71,6 +74,83 @@ func TestTagMetaRegistry(t *testing.T) {
 			}.build(),
 			input: &TestModel{},
 		},
+		{
+			name: "column and table name",
+			wantMeta: tableMetaBuilder{
+				TableName: "legacy_user",
+				Columns: []*ColumnMeta{
+					{
+						ColumnName:   "usr_id",
+						FieldName:    "Id",
+						Typ:          reflect.TypeOf(int64(0)),
+						IsPrimaryKey: true,
+						FieldIndexes: []int{0},
+					},
+					{
+						ColumnName:   "usr_nm",
+						FieldName:    "Name",
+						Typ:          reflect.TypeOf(""),
+						Offset:       8,
+						FieldIndexes: []int{1},
+					},
+					{
+						ColumnName:   "age",
+						FieldName:    "Age",
+						Typ:          reflect.TypeOf(int8(0)),
+						Offset:       24,
+						FieldIndexes: []int{2},
+					},
+				},
+				Typ: reflect.TypeOf(&LegacyUser{}),
+			}.build(),
+			input: &LegacyUser{},
+		},
+		{
+			name: "nil pointer with table name",
+			wantMeta: tableMetaBuilder{
+				TableName: "legacy_user",
+				Columns: []*ColumnMeta{
+					{
+						ColumnName:   "usr_id",
+						FieldName:    "Id",
+						Typ:          reflect.TypeOf(int64(0)),
+						IsPrimaryKey: true,
+						FieldIndexes: []int{0},
+					},
+					{
+						ColumnName:   "usr_nm",
+						FieldName:    "Name",
+						Typ:          reflect.TypeOf(""),
+						Offset:       8,
+						FieldIndexes: []int{1},
+					},
+					{
+						ColumnName:   "age",
+						FieldName:    "Age",
+						Typ:          reflect.TypeOf(int8(0)),
+						Offset:       24,
+						FieldIndexes: []int{2},
+					},
+				},
+				Typ: reflect.TypeOf(&LegacyUser{}),
+			}.build(),
+			input: (*LegacyUser)(nil),
+		},
+		{
+			name: "empty column name",
+			input: &struct {
+				Name string `eorm:"column="`
+			}{},
+			wantErr: errs.NewInvalidTagContentError(".Name"),
+		},
+		{
+			name: "column conflict",
+			input: &struct {
+				Name     string `eorm:"column=nick_name"`
+				NickName string
+			}{},
+			wantErr: errs.NewFieldConflictError(".nick_name"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -150,8 +230,68 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 		},
 		// 指针组合
 		{
-			name:    "指针组合",
-			input:   &TestCombinedModelPtr{},
+			name: "指针组合",
+			wantMeta: tableMetaBuilder{
+				TableName: "test_combined_model_ptr",
+				Columns: []*ColumnMeta{
+					{
+						ColumnName:   "create_time",
+						FieldName:    "CreateTime",
+						Typ:          reflect.TypeOf(uint64(0)),
+						Offset:       0,
+						FieldIndexes: []int{0, 0},
+						Embedded: []EmbeddedPtr{
+							{Offset: 0, Typ: reflect.TypeOf(BaseEntity{})},
+						},
+					},
+					{
+						ColumnName:   "update_time",
+						FieldName:    "UpdateTime",
+						Typ:          reflect.TypeOf(uint64(0)),
+						Offset:       8,
+						FieldIndexes: []int{0, 1},
+						Embedded: []EmbeddedPtr{
+							{Offset: 0, Typ: reflect.TypeOf(BaseEntity{})},
+						},
+					},
+					{
+						ColumnName:   "id",
+						FieldName:    "Id",
+						Typ:          reflect.TypeOf(int64(0)),
+						IsPrimaryKey: true,
+						Offset:       8,
+						FieldIndexes: []int{1},
+					},
+					{
+						ColumnName:   "first_name",
+						FieldName:    "FirstName",
+						Typ:          reflect.TypeOf(""),
+						Offset:       16,
+						FieldIndexes: []int{2},
+					},
+					{
+						ColumnName:   "age",
+						FieldName:    "Age",
+						Typ:          reflect.TypeOf(int8(0)),
+						Offset:       32,
+						FieldIndexes: []int{3},
+					},
+					{
+						ColumnName:   "last_name",
+						FieldName:    "LastName",
+						Typ:          reflect.TypeOf((*string)(nil)),
+						Offset:       40,
+						FieldIndexes: []int{4},
+					},
+				},
+				Typ: reflect.TypeOf(&TestCombinedModelPtr{}),
+			}.build(),
+			input: &TestCombinedModelPtr{},
+		},
+		// 组合的不是结构体
+		{
+			name:    "组合的不是结构体",
+			input:   &TestCombinedModelNotStruct{},
 			wantErr: errs.ErrCombinationIsNotStruct,
 		},
 		// 忽略组合
@@ -340,6 +480,173 @@ func TestTagMetaRegistry_Combination(t *testing.T) {
 	}
 }
 
+func TestWithTableName(t *testing.T) {
+	registry := &tagMetaRegistry{}
+	meta, err := registry.Register(&LegacyUser{}, WithTableName("usr_tab"))
+	require.NoError(t, err)
+	assert.Equal(t, "usr_tab", meta.TableName)
+	meta, err = registry.Register(&TestModel{}, WithTableName("test_model_tab"))
+	require.NoError(t, err)
+	assert.Equal(t, "test_model_tab", meta.TableName)
+}
+
+func TestTagMetaRegistry_AutoTime(t *testing.T) {
+	type AutoTimeModel struct {
+		Id         int64      `eorm:"primary_key"`
+		CreateTime int64      `eorm:"autoCreateTime"`
+		UpdateTime uint64     `eorm:"autoUpdateTime:milli"`
+		CreatedAt  time.Time  `eorm:"autoCreateTime"`
+		UpdatedAt  *time.Time `eorm:"autoUpdateTime"`
+	}
+	type InvalidUnit struct {
+		CreateTime int64 `eorm:"autoCreateTime:nano"`
+	}
+	type InvalidType struct {
+		CreateTime string `eorm:"autoCreateTime"`
+	}
+	type MilliTime struct {
+		UpdatedAt time.Time `eorm:"autoUpdateTime:milli"`
+	}
+	type SameUnit struct {
+		UpdateTime int64 `eorm:"autoCreateTime:milli,autoUpdateTime:milli"`
+	}
+	type UnitConflict struct {
+		UpdateTime int64 `eorm:"autoUpdateTime:milli,softDelete"`
+	}
+	registry := &tagMetaRegistry{}
+	meta, err := registry.Register(&AutoTimeModel{})
+	require.NoError(t, err)
+	assert.False(t, meta.FieldMap["Id"].AutoCreateTime || meta.FieldMap["Id"].AutoUpdateTime)
+	assert.True(t, meta.FieldMap["CreateTime"].AutoCreateTime)
+	assert.Equal(t, TimeUnitSecond, meta.FieldMap["CreateTime"].TimeUnit)
+	assert.True(t, meta.FieldMap["UpdateTime"].AutoUpdateTime)
+	assert.Equal(t, TimeUnitMilli, meta.FieldMap["UpdateTime"].TimeUnit)
+	assert.True(t, meta.FieldMap["CreatedAt"].AutoCreateTime)
+	assert.True(t, meta.FieldMap["UpdatedAt"].AutoUpdateTime)
+
+	now := time.UnixMilli(1700000000123)
+	assert.Equal(t, int64(1700000000), meta.FieldMap["CreateTime"].TimeValue(now))
+	assert.Equal(t, uint64(1700000000123), meta.FieldMap["UpdateTime"].TimeValue(now))
+	assert.Equal(t, now, meta.FieldMap["CreatedAt"].TimeValue(now))
+	assert.Equal(t, &now, meta.FieldMap["UpdatedAt"].TimeValue(now))
+
+	_, err = registry.Register(&InvalidUnit{})
+	assert.Equal(t, errs.NewInvalidTagContentError("InvalidUnit.CreateTime"), err)
+	_, err = registry.Register(&InvalidType{})
+	assert.Equal(t, errs.NewInvalidTagContentError("InvalidType.CreateTime"), err)
+	_, err = registry.Register(&MilliTime{})
+	assert.Equal(t, errs.NewInvalidTagContentError("MilliTime.UpdatedAt"), err)
+
+	meta, err = registry.Register(&SameUnit{})
+	require.NoError(t, err)
+	assert.True(t, meta.FieldMap["UpdateTime"].AutoCreateTime && meta.FieldMap["UpdateTime"].AutoUpdateTime)
+	assert.Equal(t, TimeUnitMilli, meta.FieldMap["UpdateTime"].TimeUnit)
+	_, err = registry.Register(&UnitConflict{})
+	assert.Equal(t, errs.NewInvalidTagContentError("UnitConflict.UpdateTime"), err)
+}
+
+func TestTagMetaRegistry_SoftDelete(t *testing.T) {
+	type DeletedAt struct {
+		Id        int64      `eorm:"primary_key"`
+		DeletedAt *time.Time `eorm:"softDelete"`
+	}
+	type NullDeletedAt struct {
+		DeletedAt sql.NullTime `eorm:"softDelete"`
+	}
+	type IsDeleted struct {
+		IsDeleted bool `eorm:"softDelete"`
+	}
+	type DeleteTime struct {
+		DeleteTime int64 `eorm:"softDelete:milli"`
+	}
+	type NoSoftDelete struct {
+		Id int64
+	}
+	type InvalidType struct {
+		DeletedAt time.Time `eorm:"softDelete"`
+	}
+	type InvalidUnit struct {
+		IsDeleted bool `eorm:"softDelete:milli"`
+	}
+	type Duplicate struct {
+		IsDeleted bool       `eorm:"softDelete"`
+		DeletedAt *time.Time `eorm:"softDelete"`
+	}
+	now := time.UnixMilli(1700000000123)
+	testCases := []struct {
+		name           string
+		input          any
+		wantField      string
+		wantDeleted    any
+		wantNotDeleted any
+		wantErr        error
+	}{
+		{
+			name:           "pointer time",
+			input:          &DeletedAt{},
+			wantField:      "DeletedAt",
+			wantDeleted:    &now,
+			wantNotDeleted: nil,
+		},
+		{
+			name:           "null time",
+			input:          &NullDeletedAt{},
+			wantField:      "DeletedAt",
+			wantDeleted:    sql.NullTime{Time: now, Valid: true},
+			wantNotDeleted: nil,
+		},
+		{
+			name:           "bool",
+			input:          &IsDeleted{},
+			wantField:      "IsDeleted",
+			wantDeleted:    true,
+			wantNotDeleted: false,
+		},
+		{
+			name:           "milli",
+			input:          &DeleteTime{},
+			wantField:      "DeleteTime",
+			wantDeleted:    int64(1700000000123),
+			wantNotDeleted: int64(0),
+		},
+		{
+			name:  "no soft delete",
+			input: &NoSoftDelete{},
+		},
+		{
+			name:    "invalid type",
+			input:   &InvalidType{},
+			wantErr: errs.NewInvalidTagContentError("InvalidType.DeletedAt"),
+		},
+		{
+			name:    "invalid unit",
+			input:   &InvalidUnit{},
+			wantErr: errs.NewInvalidTagContentError("InvalidUnit.IsDeleted"),
+		},
+		{
+			name:    "duplicate",
+			input:   &Duplicate{},
+			wantErr: errs.NewInvalidTagContentError("Duplicate.DeletedAt"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meta, err := (&tagMetaRegistry{}).Register(tc.input)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			if tc.wantField == "" {
+				assert.Nil(t, meta.SoftDeleteColumn)
+				return
+			}
+			assert.Equal(t, meta.FieldMap[tc.wantField], meta.SoftDeleteColumn)
+			assert.Equal(t, tc.wantDeleted, meta.SoftDeleteColumn.DeletedValue(now))
+			assert.Equal(t, tc.wantNotDeleted, meta.SoftDeleteColumn.NotDeletedValue())
+		})
+	}
+}
+
 func TestIgnoreFieldsOption(t *testing.T) {
 	tm := &TestIgnoreModel{}
 	registry := &tagMetaRegistry{}
@@ -495,6 +802,16 @@ func (t tableMetaBuilder) build() *TableMeta {
 	return res
 }
 
+type LegacyUser struct {
+	Id   int64  `eorm:"primary_key,column=usr_id"`
+	Name string `eorm:"column=usr_nm"`
+	Age  int8
+}
+
+func (LegacyUser) TableName() string {
+	return "legacy_user"
+}
+
 type TestModel struct {
 	Id        int64 `eorm:"primary_key"`
 	FirstName string
@@ -534,6 +851,13 @@ type TestCombinedModelPtr struct {
 	LastName  *string
 }
 
+type Age int
+
+type TestCombinedModelNotStruct struct {
+	*Age
+	Id int64 `eorm:"primary_key"`
+}
+
 type TestCombinedModelIgnore struct {
 	BaseEntity `eorm:"-"`
 	Id         int64 `eorm:"primary_key"`
@@ -0,0 +1,153 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy 决定了模型对应的表名和列名
+// 使用 column 标签或者 TableName 方法指定的名字优先级更高
+type NamingStrategy interface {
+	// TableName 根据结构体的名字返回表名
+	TableName(structName string) string
+	// ColumnName 根据字段名返回列名
+	ColumnName(fieldName string) string
+}
+
+// Underscore 返回默认的命名策略，每一个大写字母都是一个新单词的开始，
+// 例如 UserName 对应的是 user_name，而 UserID 对应的是 user_i_d
+func Underscore() NamingStrategy {
+	return underscore{}
+}
+
+// SnakeCase 返回使用下划线风格的命名策略，和 Underscore 不同的是，
+// 连续的大写字母会被认为是一个缩写，例如 UserID 对应的是 user_id，HTTPServer 对应的是 http_server
+func SnakeCase() NamingStrategy {
+	return snakeCase{}
+}
+
+// CamelCase 返回使用小驼峰风格的命名策略，例如 UserID 对应的是 userID，HTTPServer 对应的是 httpServer
+func CamelCase() NamingStrategy {
+	return camelCase{}
+}
+
+// TablePrefix 返回在 base 的基础上给表名加上前缀的命名策略，列名保持不变
+// 例如 TablePrefix("t_", SnakeCase()) 中，UserInfo 对应的表名是 t_user_info
+func TablePrefix(prefix string, base NamingStrategy) NamingStrategy {
+	return tablePrefix{prefix: prefix, base: base}
+}
+
+type underscore struct{}
+
+func (underscore) TableName(structName string) string {
+	return underscoreName(structName)
+}
+
+func (underscore) ColumnName(fieldName string) string {
+	return underscoreName(fieldName)
+}
+
+type snakeCase struct{}
+
+func (snakeCase) TableName(structName string) string {
+	return toSnakeCase(structName)
+}
+
+func (snakeCase) ColumnName(fieldName string) string {
+	return toSnakeCase(fieldName)
+}
+
+type camelCase struct{}
+
+func (camelCase) TableName(structName string) string {
+	return toCamelCase(structName)
+}
+
+func (camelCase) ColumnName(fieldName string) string {
+	return toCamelCase(fieldName)
+}
+
+type tablePrefix struct {
+	prefix string
+	base   NamingStrategy
+}
+
+func (t tablePrefix) TableName(structName string) string {
+	return t.prefix + t.base.TableName(structName)
+}
+
+func (t tablePrefix) ColumnName(fieldName string) string {
+	return t.base.ColumnName(fieldName)
+}
+
+// underscoreName function mainly converts upper case to lower case and adds an underscore in between
+func underscoreName(tableName string) string {
+	var buf []byte
+	for i, v := range tableName {
+		if unicode.IsUpper(v) {
+			if i != 0 {
+				buf = append(buf, '_')
+			}
+			buf = append(buf, byte(unicode.ToLower(v)))
+		} else {
+			buf = append(buf, byte(v))
+		}
+
+	}
+	return string(buf)
+}
+
+// toSnakeCase 在单词之间加上下划线，并且转为小写
+// 一个大写字母在以下两种情况下是新单词的开始：
+// 前一个字符是小写字母或者数字，例如 UserId 中的 I；
+// 前一个字符是大写字母，而后一个字符是小写字母，例如 HTTPServer 中的 S
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	sb.Grow(len(name) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+					(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+					sb.WriteByte('_')
+				}
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// toCamelCase 将开头的单词转为小写，其余部分保持不变
+// 例如 HTTPServer 开头的单词是 HTTP，而 S 属于下一个单词
+func toCamelCase(name string) string {
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
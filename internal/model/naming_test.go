@@ -0,0 +1,145 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingStrategy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		naming     NamingStrategy
+		input      string
+		wantTable  string
+		wantColumn string
+	}{
+		{
+			name:       "underscore",
+			naming:     Underscore(),
+			input:      "UserName",
+			wantTable:  "user_name",
+			wantColumn: "user_name",
+		},
+		{
+			name:       "underscore acronym",
+			naming:     Underscore(),
+			input:      "UserID",
+			wantTable:  "user_i_d",
+			wantColumn: "user_i_d",
+		},
+		{
+			name:       "snake case",
+			naming:     SnakeCase(),
+			input:      "UserName",
+			wantTable:  "user_name",
+			wantColumn: "user_name",
+		},
+		{
+			name:       "snake case acronym",
+			naming:     SnakeCase(),
+			input:      "UserID",
+			wantTable:  "user_id",
+			wantColumn: "user_id",
+		},
+		{
+			name:       "snake case leading acronym",
+			naming:     SnakeCase(),
+			input:      "HTTPServerURL",
+			wantTable:  "http_server_url",
+			wantColumn: "http_server_url",
+		},
+		{
+			name:       "snake case digit",
+			naming:     SnakeCase(),
+			input:      "UsingCol1Name",
+			wantTable:  "using_col1_name",
+			wantColumn: "using_col1_name",
+		},
+		{
+			name:       "snake case non-ASCII",
+			naming:     SnakeCase(),
+			input:      "ÜberName",
+			wantTable:  "über_name",
+			wantColumn: "über_name",
+		},
+		{
+			name:       "camel case",
+			naming:     CamelCase(),
+			input:      "UserName",
+			wantTable:  "userName",
+			wantColumn: "userName",
+		},
+		{
+			name:       "camel case acronym",
+			naming:     CamelCase(),
+			input:      "HTTPServer",
+			wantTable:  "httpServer",
+			wantColumn: "httpServer",
+		},
+		{
+			name:       "camel case all upper",
+			naming:     CamelCase(),
+			input:      "ID",
+			wantTable:  "id",
+			wantColumn: "id",
+		},
+		{
+			name:       "table prefix",
+			naming:     TablePrefix("t_", SnakeCase()),
+			input:      "UserID",
+			wantTable:  "t_user_id",
+			wantColumn: "user_id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantTable, tc.naming.TableName(tc.input))
+			assert.Equal(t, tc.wantColumn, tc.naming.ColumnName(tc.input))
+		})
+	}
+}
+
+func TestWithNamingStrategy(t *testing.T) {
+	type UserInfo struct {
+		UserID   int64 `eorm:"primary_key"`
+		NickName string
+		Email    string `eorm:"column=mail"`
+	}
+	r := NewTagMetaRegistry(WithNamingStrategy(TablePrefix("t_", CamelCase())))
+	meta, err := r.Get(&UserInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, "t_userInfo", meta.TableName)
+	assert.Equal(t, "userID", meta.FieldMap["UserID"].ColumnName)
+	assert.Equal(t, "nickName", meta.FieldMap["NickName"].ColumnName)
+	assert.Equal(t, "mail", meta.FieldMap["Email"].ColumnName)
+	assert.Equal(t, meta.FieldMap["NickName"], meta.ColumnMap["nickName"])
+
+	// TableName 方法的优先级高于命名策略
+	meta, err = r.Get(&LegacyUser{})
+	require.NoError(t, err)
+	assert.Equal(t, "legacy_user", meta.TableName)
+	assert.Equal(t, "age", meta.FieldMap["Age"].ColumnName)
+
+	// 默认的命名策略不会识别缩写
+	meta, err = NewTagMetaRegistry().Get(&UserInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, "user_info", meta.TableName)
+	assert.Equal(t, "user_i_d", meta.FieldMap["UserID"].ColumnName)
+}
@@ -32,9 +32,10 @@ var (
 	OpNEQ  = Op{Symbol: "!=", Text: "!="}
 	OpAdd  = Op{Symbol: "+", Text: "+"}
 	// OpIn   = Op{Symbol: "IN", Text: " IN "}
-	// OpMinus = Op{Symbol:"-", Text: "-"}
-	OpMulti = Op{Symbol: "*", Text: "*"}
-	// OpDiv = Op{Symbol:"/", Text: "/"}
+	OpMinus   = Op{Symbol: "-", Text: "-"}
+	OpMulti   = Op{Symbol: "*", Text: "*"}
+	OpDiv     = Op{Symbol: "/", Text: "/"}
+	OpMod     = Op{Symbol: "%", Text: "%"}
 	OpAnd     = Op{Symbol: "AND", Text: " AND "}
 	OpOr      = Op{Symbol: "OR", Text: " OR "}
 	OpNot     = Op{Symbol: "NOT", Text: "NOT "}
@@ -44,6 +45,11 @@ var (
 	OpLike    = Op{Symbol: "LIKE", Text: " LIKE "}
 	OpNotLike = Op{Symbol: "NOT LIKE", Text: " NOT LIKE "}
 	OpExist   = Op{Symbol: "EXIST", Text: "EXIST "}
+
+	OpIsNull     = Op{Symbol: "IS NULL", Text: " IS NULL"}
+	OpNotNull    = Op{Symbol: "IS NOT NULL", Text: " IS NOT NULL"}
+	OpBetween    = Op{Symbol: "BETWEEN", Text: " BETWEEN "}
+	OpNotBetween = Op{Symbol: "NOT BETWEEN", Text: " NOT BETWEEN "}
 )
 
 func NegateOp(op Op) (Op, error) {
@@ -64,6 +70,14 @@ func NegateOp(op Op) (Op, error) {
 		return OpLT, nil
 	case OpLTEQ:
 		return OpGT, nil
+	case OpIsNull:
+		return OpNotNull, nil
+	case OpNotNull:
+		return OpIsNull, nil
+	case OpBetween:
+		return OpNotBetween, nil
+	case OpNotBetween:
+		return OpBetween, nil
 	default:
 		return emptyOp, errs.NewUnsupportedOperatorError(op.Text)
 	}
@@ -26,7 +26,7 @@ import (
 
 // SimpleStruct 包含所有 eorm 支持的类型
 type SimpleStruct struct {
-	Id      uint64 `eorm:"primary_key,column=int_c"`
+	Id      uint64 `eorm:"primary_key"`
 	Bool    bool
 	BoolPtr *bool
 
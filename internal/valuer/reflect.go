@@ -42,20 +42,36 @@ func NewReflectValue(val interface{}, meta *model.TableMeta) Value {
 
 // Field 返回字段值
 func (r reflectValue) Field(name string) (reflect.Value, error) {
-	res, ok := r.fieldByIndex(name)
+	cm, ok := r.meta.FieldMap[name]
 	if !ok {
 		return reflect.Value{}, errs.NewInvalidFieldError(name)
 	}
+	res, ok := r.fieldByIndex(name, false)
+	if !ok {
+		// 组合的指针是 nil，那么就当作 NULL
+		return reflect.Zero(reflect.PointerTo(cm.Typ)), nil
+	}
 	return res, nil
 }
 
-func (r reflectValue) fieldByIndex(name string) (reflect.Value, bool) {
+// fieldByIndex 沿着 FieldIndexes 找到字段，中间可能需要跨越指针组合。
+// 如果组合的指针是 nil，alloc 为 true 的时候会创建一个新的实例，否则返回 false
+func (r reflectValue) fieldByIndex(name string, alloc bool) (reflect.Value, bool) {
 	cm, ok := r.meta.FieldMap[name]
 	if !ok {
 		return reflect.Value{}, false
 	}
 	value := r.val
 	for _, i := range cm.FieldIndexes {
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
 		value = value.Field(i)
 	}
 	return value, true
@@ -91,7 +107,7 @@ func (r reflectValue) SetColumns(rows rows.Rows) error {
 
 	for i, c := range cs {
 		cm := r.meta.ColumnMap[c]
-		fd, _ := r.fieldByIndex(cm.FieldName)
+		fd, _ := r.fieldByIndex(cm.FieldName, true)
 		fd.Set(colEleValues[i])
 	}
 	return nil
@@ -121,14 +121,14 @@ func BenchmarkReflectValue_fieldByIndexes_VS_FieldByName(b *testing.B) {
 	var fieldValue reflect.Value
 	b.Run("fieldByIndex found", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			val, ok := in.fieldByIndex(fieldName)
+			val, ok := in.fieldByIndex(fieldName, false)
 			assert.True(b, ok)
 			assert.Equal(b, fieldValue, val.Interface())
 		}
 	})
 	b.Run("fieldByIndex not found", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			val, ok := in.fieldByIndex(unknownFieldName)
+			val, ok := in.fieldByIndex(unknownFieldName, false)
 			assert.False(b, ok)
 			assert.Equal(b, unknownValue, val)
 		}
@@ -46,11 +46,32 @@ func (u unsafeValue) Field(name string) (reflect.Value, error) {
 	if !ok {
 		return reflect.Value{}, errs.NewInvalidFieldError(name)
 	}
-	ptr := unsafe.Pointer(uintptr(u.addr) + fd.Offset)
+	ptr, ok := u.fieldPtr(fd, false)
+	if !ok {
+		// 组合的指针是 nil，那么就当作 NULL
+		return reflect.Zero(reflect.PointerTo(fd.Typ)), nil
+	}
 	val := reflect.NewAt(fd.Typ, ptr).Elem()
 	return val, nil
 }
 
+// fieldPtr 计算字段的地址，需要跨越指针组合。
+// 如果组合的指针是 nil，alloc 为 true 的时候会创建一个新的实例，否则返回 false
+func (u unsafeValue) fieldPtr(cm *model.ColumnMeta, alloc bool) (unsafe.Pointer, bool) {
+	addr := u.addr
+	for _, e := range cm.Embedded {
+		pp := (*unsafe.Pointer)(unsafe.Pointer(uintptr(addr) + e.Offset))
+		if *pp == nil {
+			if !alloc {
+				return nil, false
+			}
+			*pp = reflect.New(e.Typ).UnsafePointer()
+		}
+		addr = *pp
+	}
+	return unsafe.Pointer(uintptr(addr) + cm.Offset), true
+}
+
 func (u unsafeValue) SetColumns(rows rows.Rows) error {
 
 	cs, err := rows.Columns()
@@ -68,7 +89,7 @@ func (u unsafeValue) SetColumns(rows rows.Rows) error {
 		if !ok {
 			return errs.NewInvalidColumnError(c)
 		}
-		ptr := unsafe.Pointer(uintptr(u.addr) + cm.Offset)
+		ptr, _ := u.fieldPtr(cm, true)
 		val := reflect.NewAt(cm.Typ, ptr)
 		colValues[i] = val.Interface()
 	}
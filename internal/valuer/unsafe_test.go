@@ -26,6 +26,7 @@ import (
 	"github.com/ecodeclub/eorm/internal/model"
 	"github.com/ecodeclub/eorm/internal/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_unsafeValue_Field(t *testing.T) {
@@ -211,6 +212,53 @@ func testSetColumn(t *testing.T, creator Creator) {
 		}
 		assert.Equal(t, wantUser, u)
 	})
+
+	type PtrCombinedUser struct {
+		*BaseEntity
+		FirstName string
+	}
+
+	// 测试使用指针组合的场景
+	t.Run("pointer combination", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = db.Close() }()
+
+		u := &PtrCombinedUser{}
+		meta, err := r.Get(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		val := creator(u, meta)
+
+		// 组合的指针是 nil 的时候，字段被当作 NULL
+		fd, err := val.Field("Id")
+		require.NoError(t, err)
+		assert.Nil(t, fd.Interface())
+		assert.True(t, fd.IsNil())
+
+		mock.ExpectQuery("SELECT *").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "create_time", "first_name"}).
+				AddRow(123, 100000, "Tom"))
+		rows, _ := db.Query("SELECT *")
+		rows.Next()
+		err = val.SetColumns(rows)
+		assert.NoError(t, err)
+		wantUser := &PtrCombinedUser{
+			BaseEntity: &BaseEntity{
+				Id:         123,
+				CreateTime: 100000,
+			},
+			FirstName: "Tom",
+		}
+		assert.Equal(t, wantUser, u)
+
+		fd, err = val.Field("Id")
+		require.NoError(t, err)
+		assert.Equal(t, int64(123), fd.Interface())
+	})
 }
 
 func testValueField(t *testing.T, creator Creator) {
@@ -25,6 +25,7 @@ import (
 	"github.com/ecodeclub/eorm"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMiddlewareBuilder_Build(t *testing.T) {
@@ -120,6 +121,33 @@ func TestMiddlewareBuilder_Build(t *testing.T) {
 
 }
 
+func TestMiddlewareBuilder_Comment(t *testing.T) {
+	var logged []string
+	mdl := NewBuilder().LogFunc(func(sql string, args ...any) {
+		logged = append(logged, sql)
+	}).Build()
+	orm, err := eorm.Open("sqlite3", "file:test.db?cache=shared&mode=memory",
+		eorm.DBWithMiddlewares(mdl),
+		eorm.DBWithComment(func(ctx context.Context) map[string]string {
+			return map[string]string{"trace_id": "123"}
+		}))
+	require.NoError(t, err)
+	defer func() {
+		_ = orm.Close()
+	}()
+
+	ctx := context.Background()
+	res, err := eorm.RawQuery[int](orm, "SELECT 1;").Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, *res)
+	err = eorm.RawQuery[any](orm, "CREATE TABLE IF NOT EXISTS `comment_model`(`id` INTEGER);").Exec(ctx).Err()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/* trace_id='123' */ SELECT 1;",
+		"/* trace_id='123' */ CREATE TABLE IF NOT EXISTS `comment_model`(`id` INTEGER);",
+	}, logged)
+}
+
 type testMiddlewareBuilder struct {
 	*MiddlewareBuilder
 	printVal strings.Builder
@@ -25,7 +25,10 @@ var (
 	opEQ      = operator.OpEQ
 	opNEQ     = operator.OpNEQ
 	opAdd     = operator.OpAdd
+	opMinus   = operator.OpMinus
 	opMulti   = operator.OpMulti
+	opDiv     = operator.OpDiv
+	opMod     = operator.OpMod
 	opAnd     = operator.OpAnd
 	opOr      = operator.OpOr
 	opNot     = operator.OpNot
@@ -35,6 +38,11 @@ var (
 	opLike    = operator.OpLike
 	opNotLike = operator.OpNotLike
 	opExist   = operator.OpExist
+
+	opIsNull     = operator.OpIsNull
+	opNotNull    = operator.OpNotNull
+	opBetween    = operator.OpBetween
+	opNotBetween = operator.OpNotBetween
 )
 
 // Predicate will be used in Where Or Having
@@ -87,6 +87,46 @@ func TestPredicate_C(t *testing.T) {
 			wantSql:  "SELECT `id` FROM `test_model` WHERE `age`>(`id`*(`age`+?));",
 			wantArgs: []interface{}{66},
 		},
+		{
+			name: "is null",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(C("LastName").IsNull().Or(C("FirstName").NotNull())),
+			wantSql: "SELECT `id` FROM `test_model` WHERE (`last_name` IS NULL) OR (`first_name` IS NOT NULL);",
+		},
+		{
+			name: "not is null",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(Not(C("LastName").IsNull())),
+			wantSql: "SELECT `id` FROM `test_model` WHERE NOT (`last_name` IS NULL);",
+		},
+		{
+			name: "between",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(C("Age").Between(18, 30), C("Id").NotBetween(1, 10)),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE (`age` BETWEEN ? AND ?) AND (`id` NOT BETWEEN ? AND ?);",
+			wantArgs: []interface{}{18, 30, 1, 10},
+		},
+		{
+			name: "between columns",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(C("Age").Between(C("Id"), C("Id").Add(10))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `age` BETWEEN `id` AND (`id`+?);",
+			wantArgs: []interface{}{10},
+		},
+		{
+			name: "sub div mod",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(C("Age").GT(C("Id").Div(2)), C("Id").EQ(C("Age").Mod(2)), C("Id").LT(C("Age").Sub(1))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE ((`age`>(`id`/?)) AND (`id`=(`age`%?))) AND (`id`<(`age`-?));",
+			wantArgs: []interface{}{2, 2, 1},
+		},
+		{
+			name: "math chain",
+			builder: NewSelector[TestModel](db).Select(Columns("Id")).
+				Where(C("Age").GT(C("Id").Multi(2).Sub(1).Div(C("Age")).Mod(3))),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `age`>((((`id`*?)-?)/`age`)%?);",
+			wantArgs: []interface{}{2, 1, 3},
+		},
 		{
 			name:     "Avg with EQ",
 			builder:  NewSelector[TestModel](db).Select().GroupBy("FirstName").Having(Avg("Age").EQ(18)),
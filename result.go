@@ -38,3 +38,45 @@ func (r Result) RowsAffected() (int64, error) {
 	}
 	return r.res.RowsAffected()
 }
+
+var _ sql.Result = batchResult{}
+
+// batchResult 是分批执行的结果
+type batchResult []sql.Result
+
+// LastInsertId 返回最后一批的 LastInsertId
+func (b batchResult) LastInsertId() (int64, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	return b[len(b)-1].LastInsertId()
+}
+
+// RowsAffected 返回所有批次影响行数的总和
+func (b batchResult) RowsAffected() (int64, error) {
+	var sum int64
+	for _, r := range b {
+		n, err := r.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		sum += n
+	}
+	return sum, nil
+}
+
+var _ sql.Result = returningResult{}
+
+// returningResult 是通过 RETURNING 获得自增主键时的执行结果
+type returningResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r returningResult) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r returningResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
@@ -17,6 +17,7 @@ package eorm
 import (
 	"context"
 
+	"github.com/ecodeclub/eorm/internal/dialect"
 	"github.com/ecodeclub/eorm/internal/errs"
 	"github.com/valyala/bytebufferpool"
 )
@@ -28,6 +29,11 @@ type Selector[T any] struct {
 	Session
 	selectorBuilder
 	table TableReference
+	// ctes 是 WITH 子句中定义的 CTE
+	ctes      []Subquery
+	recursive bool
+	// groupByExprs 是 GROUP BY 中的表达式，排在 groupBy 之后
+	groupByExprs []Expr
 }
 
 // NewSelector 创建一个 Selector
@@ -56,22 +62,49 @@ func (s *Selector[T]) tableOf() any {
 
 // Build returns Select Query
 func (s *Selector[T]) Build() (Query, error) {
-	defer bytebufferpool.Put(s.buffer)
+	// 同一个 Subquery 可能会被引用多次，例如同时出现在 SELECT 和 WHERE 部分，
+	// 所以 Build 可能会被调用多次，每次都需要重新获取 buffer，并且清空参数
+	if s.buffer == nil {
+		s.buffer = bytebufferpool.Get()
+	}
+	s.args = nil
+	defer func() {
+		bytebufferpool.Put(s.buffer)
+		s.buffer = nil
+	}()
 	var err error
 	s.meta, err = s.metaRegistry.Get(s.tableOf())
 	if err != nil {
 		return EmptyQuery, err
 	}
+	if len(s.ctes) > 0 {
+		if err = s.buildWith(); err != nil {
+			return EmptyQuery, err
+		}
+	}
 	s.writeString("SELECT ")
+	if len(s.hints) > 0 {
+		if err = s.buildHints(s.hints); err != nil {
+			return EmptyQuery, err
+		}
+	}
 	if s.distinct {
 		s.writeString("DISTINCT ")
 	}
 	if len(s.columns) == 0 {
-		switch s.table.(type) {
+		switch tbl := s.table.(type) {
 		case Table, nil:
 			if err = s.buildAllColumns(); err != nil {
 				return EmptyQuery, err
 			}
+		case Subquery:
+			// 直接查询 CTE 的时候，和查询普通表一样，使用 T 的所有列
+			if !s.isCTE(tbl) {
+				return EmptyQuery, errs.NewMustSpecifyColumnsError()
+			}
+			if err = s.buildAllColumns(); err != nil {
+				return EmptyQuery, err
+			}
 		default:
 			return EmptyQuery, errs.NewMustSpecifyColumnsError()
 		}
@@ -81,31 +114,45 @@ func (s *Selector[T]) Build() (Query, error) {
 			return EmptyQuery, err
 		}
 	}
+	// 软删除的条件可能会放在 JOIN 的 ON 里面，所以要在构造表之前计算
+	table, where := s.table, s.where
+	if !s.unscoped {
+		if table, where, err = s.withNotDeleted(s.table, where); err != nil {
+			return EmptyQuery, err
+		}
+	}
 	s.writeString(" FROM ")
 
-	if err = s.buildTable(s.table); err != nil {
+	if err = s.buildTable(table); err != nil {
 		return EmptyQuery, err
 	}
-
-	if len(s.where) > 0 {
-		s.writeString(" WHERE ")
-		err = s.buildPredicates(s.where)
-		if err != nil {
+	if len(s.indexHints) > 0 {
+		// 在 JOIN 中使用索引提示的时候，无法确定是哪个表的索引
+		switch tbl := s.table.(type) {
+		case nil:
+		case Table:
+			if tbl.cte {
+				return EmptyQuery, errs.ErrIndexHintOnlySingleTable
+			}
+		default:
+			return EmptyQuery, errs.ErrIndexHintOnlySingleTable
+		}
+		if err = s.buildIndexHints(s.indexHints); err != nil {
 			return EmptyQuery, err
 		}
 	}
 
-	// group by
-	if len(s.groupBy) > 0 {
-		err = s.buildGroupBy()
+	if len(where) > 0 {
+		s.writeString(" WHERE ")
+		err = s.buildPredicates(where)
 		if err != nil {
 			return EmptyQuery, err
 		}
 	}
 
-	// order by
-	if len(s.orderBy) > 0 {
-		err = s.buildOrderBy()
+	// group by
+	if len(s.groupBy) > 0 || len(s.groupByExprs) > 0 {
+		err = s.buildGroupBy()
 		if err != nil {
 			return EmptyQuery, err
 		}
@@ -120,14 +167,17 @@ func (s *Selector[T]) Build() (Query, error) {
 		}
 	}
 
-	if s.offset > 0 {
-		s.writeString(" OFFSET ")
-		s.parameter(s.offset)
+	// order by
+	if len(s.orderBy) > 0 {
+		err = s.buildOrderBy()
+		if err != nil {
+			return EmptyQuery, err
+		}
 	}
 
-	if s.limit > 0 {
-		s.writeString(" LIMIT ")
-		s.parameter(s.limit)
+	s.buildLimitOffset(s.limit, s.offset)
+	if err = s.buildLock(s.lock); err != nil {
+		return EmptyQuery, err
 	}
 	s.end()
 	return Query{SQL: s.buffer.String(), Args: s.args}, nil
@@ -138,6 +188,10 @@ func (s *Selector[T]) buildTable(table TableReference) error {
 	case nil:
 		s.quote(s.meta.TableName)
 	case Table:
+		if t.cte {
+			s.quote(t.alias)
+			return nil
+		}
 		m, err := s.metaRegistry.Get(t.entity)
 		if err != nil {
 			return err
@@ -148,10 +202,15 @@ func (s *Selector[T]) buildTable(table TableReference) error {
 			s.quote(t.alias)
 		}
 	case Join:
-		if err := s.buildJoin(t); err != nil {
+		// JOIN 的两边也可能引用 CTE
+		if err := s.buildJoin(t, s.buildTable); err != nil {
 			return err
 		}
 	case Subquery:
+		if s.isCTE(t) {
+			s.quote(t.alias)
+			return nil
+		}
 		return s.buildSubquery(t, true)
 	default:
 		return errs.NewUnsupportedTableReferenceError(table)
@@ -159,40 +218,92 @@ func (s *Selector[T]) buildTable(table TableReference) error {
 	return nil
 }
 
-func (s *Selector[T]) buildOrderBy() error {
-	s.writeString(" ORDER BY ")
-	for i, ob := range s.orderBy {
+// buildWith 构造 WITH 子句，CTE 的参数排在整个查询的最前面
+func (s *Selector[T]) buildWith() error {
+	s.writeString("WITH ")
+	if s.recursive {
+		s.writeString("RECURSIVE ")
+	}
+	for i, cte := range s.ctes {
+		if cte.alias == "" {
+			return errs.ErrCTEWithoutName
+		}
 		if i > 0 {
 			s.comma()
 		}
-		for _, c := range ob.fields {
-			cMeta, ok := s.meta.FieldMap[c]
-			if !ok {
-				return errs.NewInvalidFieldError(c)
-			}
-			s.quote(cMeta.ColumnName)
+		s.quote(cte.alias)
+		s.writeString(" AS ")
+		if err := s.buildSubquery(cte, false); err != nil {
+			return err
 		}
-		s.space()
-		s.writeString(ob.order)
 	}
+	s.space()
 	return nil
 }
 
+// isCTE 判断 sub 是否是 WITH 子句中定义的 CTE
+func (s *Selector[T]) isCTE(sub Subquery) bool {
+	for _, cte := range s.ctes {
+		if cte.alias == sub.alias {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Selector[T]) buildOrderBy() error {
+	return s.buildOrderByList(s.orderBy, s.selectedAliases())
+}
+
 func (s *Selector[T]) buildGroupBy() error {
 	s.writeString(" GROUP BY ")
+	aliases := s.selectedAliases()
 	for i, gb := range s.groupBy {
-		cMeta, ok := s.meta.FieldMap[gb]
-		if !ok {
-			return errs.NewInvalidFieldError(gb)
-		}
 		if i > 0 {
 			s.comma()
 		}
-		s.quote(cMeta.ColumnName)
+		if err := s.buildFieldOrAlias(gb, aliases); err != nil {
+			return err
+		}
+	}
+	for i, e := range s.groupByExprs {
+		if i > 0 || len(s.groupBy) > 0 {
+			s.comma()
+		}
+		if err := s.buildExpr(e); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// selectedAliases 返回 SELECT 部分中的所有别名，
+// ORDER BY 和 GROUP BY 中可以直接使用这些别名
+func (s *Selector[T]) selectedAliases() map[string]struct{} {
+	aliases := make(map[string]struct{}, len(s.columns))
+	for _, selectable := range s.columns {
+		var alias string
+		switch expr := selectable.(type) {
+		case Column:
+			alias = expr.alias
+		case Aggregate:
+			alias = expr.alias
+		case Window:
+			alias = expr.alias
+		case FuncExpr:
+			alias = expr.alias
+		case CaseExpr:
+			alias = expr.alias
+		case Subquery:
+			alias = expr.alias
+		}
+		if alias != "" {
+			aliases[alias] = struct{}{}
+		}
+	}
+	return aliases
+}
+
 func (s *Selector[T]) buildAllColumns() error {
 	for i, cMeta := range s.meta.Columns {
 		// 永远不会返回 error
@@ -224,6 +335,30 @@ func (s *Selector[T]) buildSelectedList() error {
 			if err := s.selectAggregate(expr); err != nil {
 				return err
 			}
+		case Window:
+			if err := s.buildWindow(expr); err != nil {
+				return err
+			}
+		case FuncExpr:
+			if err := s.buildFunc(expr); err != nil {
+				return err
+			}
+			if expr.alias != "" {
+				s.writeString(" AS ")
+				s.quote(expr.alias)
+			}
+		case Subquery:
+			if err := s.buildSubquery(expr, expr.alias != ""); err != nil {
+				return err
+			}
+		case CaseExpr:
+			if err := s.buildCase(expr); err != nil {
+				return err
+			}
+			if expr.alias != "" {
+				s.writeString(" AS ")
+				s.quote(expr.alias)
+			}
 		case RawExpr:
 			s.buildRawExpr(expr)
 		}
@@ -232,25 +367,9 @@ func (s *Selector[T]) buildSelectedList() error {
 
 }
 func (s *Selector[T]) selectAggregate(aggregate Aggregate) error {
-	s.writeString(aggregate.fn)
-
-	s.writeByte('(')
-	if aggregate.distinct {
-		s.writeString("DISTINCT ")
-	}
-	cMeta, ok := s.meta.FieldMap[aggregate.arg]
-	// s.aliases[aggregate.alias] = struct{}{}
-	if !ok {
-		return errs.NewInvalidFieldError(aggregate.arg)
-	}
-	if aggregate.table != nil {
-		if alias := aggregate.table.getAlias(); alias != "" {
-			s.quote(alias)
-			s.point()
-		}
+	if err := s.buildHavingAggregate(aggregate); err != nil {
+		return err
 	}
-	s.quote(cMeta.ColumnName)
-	s.writeByte(')')
 	if aggregate.alias != "" {
 		// if _, ok := s.aliases[aggregate.alias]; ok {
 		// 	s.writeString(" AS ")
@@ -274,16 +393,24 @@ func (s *Selector[T]) buildColumns(index int, name string) error {
 	return nil
 }
 
-func (s *Selector[T]) buildUsing(using []string) error {
-	s.writeString(" USING (")
-	for i, col := range using {
-		err := s.buildColumns(i, col)
-		if err != nil {
-			return err
+// selectedColumnCount 返回 SELECT 部分的列数，必须在 Build 之后调用
+// 使用了 RawExpr 之类无法确定列数的表达式时，返回 -1
+func (s *Selector[T]) selectedColumnCount() int {
+	if len(s.columns) == 0 {
+		return len(s.meta.Columns)
+	}
+	cnt := 0
+	for _, selectable := range s.columns {
+		switch expr := selectable.(type) {
+		case Column, Aggregate, Window, CaseExpr, FuncExpr, Subquery:
+			cnt++
+		case columns:
+			cnt += len(expr.cs)
+		default:
+			return -1
 		}
 	}
-	s.writeByte(')')
-	return nil
+	return cnt
 }
 
 // Select 指定查询的列。
@@ -299,6 +426,26 @@ func (s *Selector[T]) From(tbl TableReference) *Selector[T] {
 	return s
 }
 
+// With 定义 CTE，CTE 的名字就是 Subquery 的别名
+// 定义之后，可以在 From 和 Join 中直接使用该 Subquery 来引用 CTE
+func (s *Selector[T]) With(ctes ...Subquery) *Selector[T] {
+	s.ctes = append(s.ctes, ctes...)
+	return s
+}
+
+// WithRecursive 定义递归 CTE，即 WITH RECURSIVE
+// 在 CTE 的定义中，可以使用 CTEOf 来引用自身
+func (s *Selector[T]) WithRecursive(ctes ...Subquery) *Selector[T] {
+	s.recursive = true
+	return s.With(ctes...)
+}
+
+// Unscoped 不再自动过滤被软删除的数据
+func (s *Selector[T]) Unscoped() *Selector[T] {
+	s.unscoped = true
+	return s
+}
+
 // Where accepts predicates
 func (s *Selector[T]) Where(predicates ...Predicate) *Selector[T] {
 	s.where = predicates
@@ -323,6 +470,13 @@ func (s *Selector[T]) GroupBy(columns ...string) *Selector[T] {
 	return s
 }
 
+// GroupByExprs 按照表达式分组，例如 GroupByExprs(Date(C("CreateTime")))
+// 和 GroupBy 一起使用的时候，表达式排在字段之后
+func (s *Selector[T]) GroupByExprs(exprs ...Expr) *Selector[T] {
+	s.groupByExprs = exprs
+	return s
+}
+
 // OrderBy means "ORDER BY"
 func (s *Selector[T]) OrderBy(orderBys ...OrderBy) *Selector[T] {
 	s.orderBy = orderBys
@@ -341,6 +495,57 @@ func (s *Selector[T]) Offset(offset int) *Selector[T] {
 	return s
 }
 
+// Hints 指定优化器提示，会生成 SELECT /*+ hint1 hint2 */ ...
+// 例如 Hints("MAX_EXECUTION_TIME(1000)")
+func (s *Selector[T]) Hints(hints ...string) *Selector[T] {
+	s.hints = append(s.hints, hints...)
+	return s
+}
+
+// UseIndex 即 USE INDEX，只能用于单表查询
+func (s *Selector[T]) UseIndex(indexes ...string) *Selector[T] {
+	s.indexHints = append(s.indexHints, dialect.IndexHint{Type: dialect.IndexHintUse, Indexes: indexes})
+	return s
+}
+
+// ForceIndex 即 FORCE INDEX，只能用于单表查询
+func (s *Selector[T]) ForceIndex(indexes ...string) *Selector[T] {
+	s.indexHints = append(s.indexHints, dialect.IndexHint{Type: dialect.IndexHintForce, Indexes: indexes})
+	return s
+}
+
+// IgnoreIndex 即 IGNORE INDEX，只能用于单表查询
+func (s *Selector[T]) IgnoreIndex(indexes ...string) *Selector[T] {
+	s.indexHints = append(s.indexHints, dialect.IndexHint{Type: dialect.IndexHintIgnore, Indexes: indexes})
+	return s
+}
+
+// ForUpdate 加排他锁，即 FOR UPDATE
+func (s *Selector[T]) ForUpdate() *Selector[T] {
+	s.lock.Strength = dialect.LockForUpdate
+	return s
+}
+
+// ForShare 加共享锁，即 FOR SHARE
+func (s *Selector[T]) ForShare() *Selector[T] {
+	s.lock.Strength = dialect.LockForShare
+	return s
+}
+
+// NoWait 行已经被锁住的时候，不等待而是直接返回错误
+// 必须和 ForUpdate 或者 ForShare 一起使用
+func (s *Selector[T]) NoWait() *Selector[T] {
+	s.lock.Wait = dialect.LockNoWait
+	return s
+}
+
+// SkipLocked 跳过已经被锁住的行
+// 必须和 ForUpdate 或者 ForShare 一起使用
+func (s *Selector[T]) SkipLocked() *Selector[T] {
+	s.lock.Wait = dialect.LockSkipLocked
+	return s
+}
+
 func (s *Selector[T]) AsSubquery(alias string) Subquery {
 	var table TableReference
 	if s.table == nil {
@@ -369,7 +574,51 @@ func (s *Selector[T]) Get(ctx context.Context) (*T, error) {
 // OrderBy specify fields and ASC
 type OrderBy struct {
 	fields []string
-	order  string
+	// expr 不为 nil 的时候，按照表达式排序，例如 FuncExpr.ASC()
+	expr  Expr
+	order string
+	// nulls 是 FIRST 或者 LAST，为空的时候使用数据库默认的顺序
+	nulls string
+}
+
+const (
+	nullsFirst = "FIRST"
+	nullsLast  = "LAST"
+)
+
+// NullsFirst 即 NULLS FIRST，NULL 排在最前面
+// MySQL 不支持该语法，会使用 xxx IS NULL DESC 来模拟
+func (o OrderBy) NullsFirst() OrderBy {
+	o.nulls = nullsFirst
+	return o
+}
+
+// NullsLast 即 NULLS LAST，NULL 排在最后面
+// MySQL 不支持该语法，会使用 xxx IS NULL ASC 来模拟
+func (o OrderBy) NullsLast() OrderBy {
+	o.nulls = nullsLast
+	return o
+}
+
+// OrderByExpr 按照任意的表达式排序，默认是 ASC，
+// 例如 OrderByExpr(C("Age").Add(1)).DESC()
+func OrderByExpr(e Expr) OrderBy {
+	return OrderBy{
+		expr:  e,
+		order: "ASC",
+	}
+}
+
+// ASC 将排序方向改为 ASC
+func (o OrderBy) ASC() OrderBy {
+	o.order = "ASC"
+	return o
+}
+
+// DESC 将排序方向改为 DESC
+func (o OrderBy) DESC() OrderBy {
+	o.order = "DESC"
+	return o
 }
 
 // ASC means ORDER BY fields ASC
@@ -400,29 +649,3 @@ func (s *Selector[T]) GetMulti(ctx context.Context) ([]*T, error) {
 	}
 	return newQuerier[T](s.Session, query, s.meta, SELECT).GetMulti(ctx)
 }
-
-func (s *Selector[T]) buildJoin(t Join) error {
-	s.writeByte('(')
-	if err := s.buildTable(t.left); err != nil {
-		return err
-	}
-	s.space()
-	s.writeString(t.typ)
-	s.space()
-	if err := s.buildTable(t.right); err != nil {
-		return err
-	}
-	if len(t.using) > 0 {
-		if err := s.buildUsing(t.using); err != nil {
-			return err
-		}
-	}
-	if len(t.on) > 0 {
-		s.writeString(" ON ")
-		if err := s.buildPredicates(t.on); err != nil {
-			return err
-		}
-	}
-	s.writeByte(')')
-	return nil
-}
@@ -14,6 +14,8 @@
 
 package eorm
 
+import "github.com/ecodeclub/eorm/internal/dialect"
+
 type selectorBuilderAttribute struct {
 	columns []Selectable
 	where   []Predicate
@@ -24,6 +26,12 @@ type selectorBuilderAttribute struct {
 	distinct bool
 	offset   int
 	limit    int
+	lock     dialect.Lock
+	// hints 是优化器提示，例如 MAX_EXECUTION_TIME(1000)
+	hints      []string
+	indexHints []dialect.IndexHint
+	// unscoped 为 true 的时候，不会自动过滤被软删除的数据
+	unscoped bool
 }
 
 type selectorBuilder struct {
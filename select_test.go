@@ -838,14 +838,14 @@ func TestSelectable(t *testing.T) {
 		{
 			name:     "offset",
 			builder:  NewSelector[TestModel](db).OrderBy(ASC("Age"), DESC("Id")).Offset(10),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC OFFSET ?;",
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC LIMIT -1 OFFSET ?;",
 			wantArgs: []interface{}{10},
 		},
 		{
 			name:     "limit",
 			builder:  NewSelector[TestModel](db).OrderBy(ASC("Age"), DESC("Id")).Offset(10).Limit(100),
-			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC OFFSET ? LIMIT ?;",
-			wantArgs: []interface{}{10, 100},
+			wantSql:  "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` DESC LIMIT ? OFFSET ?;",
+			wantArgs: []interface{}{100, 10},
 		},
 		{
 			name:     "where",
@@ -1091,14 +1091,14 @@ func TestSelectableCombination(t *testing.T) {
 		{
 			name:     "offset",
 			builder:  NewSelector[TestCombinedModel](db).OrderBy(ASC("Age"), DESC("CreateTime")).Offset(10),
-			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC OFFSET ?;",
+			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC LIMIT -1 OFFSET ?;",
 			wantArgs: []interface{}{10},
 		},
 		{
 			name:     "limit",
 			builder:  NewSelector[TestCombinedModel](db).OrderBy(ASC("Age"), DESC("CreateTime")).Offset(10).Limit(100),
-			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC OFFSET ? LIMIT ?;",
-			wantArgs: []interface{}{10, 100},
+			wantSql:  "SELECT `create_time`,`update_time`,`id`,`first_name`,`age`,`last_name` FROM `test_combined_model` ORDER BY `age` ASC,`create_time` DESC LIMIT ? OFFSET ?;",
+			wantArgs: []interface{}{100, 10},
 		},
 		{
 			name:     "where",
@@ -1177,7 +1177,7 @@ func ExampleSelector_OrderBy() {
 	// SQL: SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC;
 	// Args: []interface {}(nil)
 	// case2
-	// SQL: SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age``id` ASC;
+	// SQL: SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` ASC;
 	// Args: []interface {}(nil)
 	// case3
 	// SQL: SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC,`id` ASC;
@@ -1268,6 +1268,8 @@ func ExampleSelector_Distinct() {
 
 func TestSelector_Join(t *testing.T) {
 	db := memoryDB()
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
 	type Order struct {
 		Id        int
 		UsingCol1 string
@@ -1285,6 +1287,11 @@ func TestSelector_Join(t *testing.T) {
 		Id int
 	}
 
+	type OrderExt struct {
+		OrderId   int
+		UsingCol1 string `eorm:"column=ext_col1"`
+	}
+
 	testCases := []struct {
 		name      string
 		s         QueryBuilder
@@ -1346,6 +1353,16 @@ func TestSelector_Join(t *testing.T) {
 			}(),
 			wantErr: errs.NewInvalidFieldError("invalid"),
 		},
+		{
+			name: "join-using-column-conflict",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&OrderExt{}, "t2")
+				t3 := t1.Join(t2).Using("UsingCol1")
+				return NewSelector[Order](db).From(t3).Select(t1.C("Id"))
+			}(),
+			wantErr: errs.NewUsingColumnConflictError("UsingCol1"),
+		},
 		{
 			name: "join-using-cols-Avg",
 			s: func() QueryBuilder {
@@ -1580,6 +1597,118 @@ func TestSelector_Join(t *testing.T) {
 			wantQuery: Query{
 				SQL: "SELECT AVG(`t1`.`using_col1`) AS `UsingCol1` FROM (`order` AS `t1` JOIN `order_detail` AS `t2` ON `t1`.`id`=`t2`.`order_id`);",
 			},
+		}, {
+			name: "cross join",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&Item{}, "t2")
+				return NewSelector[Order](db).Select(t1.C("Id"), t2.C("Id")).From(t1.CrossJoin(t2))
+			}(),
+			wantQuery: Query{
+				SQL: "SELECT `t1`.`id`,`t2`.`id` FROM (`order` AS `t1` CROSS JOIN `item` AS `t2`);",
+			},
+		},
+		{
+			name: "natural join",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&OrderDetail{}, "t2")
+				t4 := TableOf(&Item{}, "t4")
+				return NewSelector[Order](db).Select(t1.AllColumns()).
+					From(t1.NaturalJoin(t2).CrossJoin(t4))
+			}(),
+			wantQuery: Query{
+				SQL: "SELECT `t1`.* FROM ((`order` AS `t1` NATURAL JOIN `order_detail` AS `t2`) CROSS JOIN `item` AS `t4`);",
+			},
+		},
+		{
+			name: "full outer join",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&OrderDetail{}, "t2")
+				return NewSelector[Order](db).Select(t1.AllColumns()).
+					From(t1.FullOuterJoin(t2).On(t1.C("Id").EQ(t2.C("OrderId"))))
+			}(),
+			wantQuery: Query{
+				SQL: "SELECT `t1`.* FROM (`order` AS `t1` FULL OUTER JOIN `order_detail` AS `t2` ON `t1`.`id`=`t2`.`order_id`);",
+			},
+		},
+		{
+			name: "full outer join unsupported",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&OrderDetail{}, "t2")
+				return NewSelector[Order](mysqlDB).Select(t1.AllColumns()).
+					From(t1.FullOuterJoin(t2).On(t1.C("Id").EQ(t2.C("OrderId"))))
+			}(),
+			wantErr: errs.NewUnsupportedClauseError("MySQL", "FULL OUTER JOIN"),
+		},
+		{
+			name: "straight join",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&OrderDetail{}, "t2")
+				t4 := TableOf(&Item{}, "t4")
+				return NewSelector[Order](mysqlDB).Select(t1.AllColumns()).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("OrderId"))).StraightJoin(t4).On(t2.C("ItemId").EQ(t4.C("Id"))))
+			}(),
+			wantQuery: Query{
+				SQL: "SELECT `t1`.* FROM ((`order` AS `t1` JOIN `order_detail` AS `t2` ON `t1`.`id`=`t2`.`order_id`) STRAIGHT_JOIN `item` AS `t4` ON `t2`.`item_id`=`t4`.`id`);",
+			},
+		},
+		{
+			name: "straight join unsupported",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&OrderDetail{}, "t2")
+				return NewSelector[Order](db).Select(t1.AllColumns()).
+					From(t1.StraightJoin(t2).On(t1.C("Id").EQ(t2.C("OrderId"))))
+			}(),
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "STRAIGHT_JOIN"),
+		},
+		{
+			name: "subquery join",
+			s: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).Select(C("OrderId")).AsSubquery("sub")
+				t1 := TableOf(&Order{}, "t1")
+				return NewSelector[Order](db).Select(t1.AllColumns()).From(sub.CrossJoin(t1))
+			}(),
+			wantQuery: Query{
+				SQL: "SELECT `t1`.* FROM ((SELECT `order_id` FROM `order_detail`) AS `sub` CROSS JOIN `order` AS `t1`);",
+			},
+		},
+		{
+			name: "using column missing in right table",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t4 := TableOf(&Item{}, "t4")
+				return NewSelector[Order](db).Select(t1.AllColumns()).From(t1.Join(t4).Using("UsingCol1"))
+			}(),
+			wantErr: errs.NewInvalidFieldError("UsingCol1"),
+		},
+		{
+			name: "aggregate of joined table",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&OrderDetail{}, "t2")
+				return NewSelector[Order](db).Select(t1.C("Id"), t2.Count("ItemId")).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("OrderId")))).
+					GroupByExprs(t1.C("Id")).Having(t2.Count("ItemId").GT(1))
+			}(),
+			wantQuery: Query{
+				SQL:  "SELECT `t1`.`id`,COUNT(`t2`.`item_id`) FROM (`order` AS `t1` JOIN `order_detail` AS `t2` ON `t1`.`id`=`t2`.`order_id`) GROUP BY `t1`.`id` HAVING COUNT(`t2`.`item_id`)>?;",
+				Args: []interface{}{1},
+			},
+		},
+		{
+			name: "invalid column of joined table",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}, "t1")
+				t2 := TableOf(&OrderDetail{}, "t2")
+				return NewSelector[Order](db).Select(t1.AllColumns()).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("UsingCol3"))))
+			}(),
+			wantErr: errs.NewInvalidFieldError("UsingCol3"),
 		},
 	}
 
@@ -1649,6 +1778,61 @@ func TestSelector_Subquery(t *testing.T) {
 			wantQuery: Query{
 				SQL: "SELECT `o1`.`id` FROM `order` AS `o1` WHERE `o1`.`id` IN (SELECT `order_id` FROM `order_detail`);"},
 		},
+		{
+			name: "in with args",
+			s: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).Select(C("OrderId")).Where(C("ItemId").GT(10)).AsSubquery("sub")
+				return NewSelector[Order](db).Select(C("Id")).Where(C("UsingCol1").EQ("a"), C("Id").In(sub), C("UsingCol2").EQ("b"))
+			}(),
+			wantQuery: Query{
+				SQL:  "SELECT `id` FROM `order` WHERE ((`using_col1`=?) AND (`id` IN (SELECT `order_id` FROM `order_detail` WHERE `item_id`>?))) AND (`using_col2`=?);",
+				Args: []any{"a", 10, "b"},
+			},
+		},
+		{
+			name: "not in",
+			s: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).Select(C("OrderId")).Where(C("ItemId").GT(10)).AsSubquery("sub")
+				return NewSelector[Order](db).Select(C("Id")).Where(C("Id").NotIn(sub))
+			}(),
+			wantQuery: Query{
+				SQL:  "SELECT `id` FROM `order` WHERE `id` NOT IN (SELECT `order_id` FROM `order_detail` WHERE `item_id`>?);",
+				Args: []any{10},
+			},
+		},
+		{
+			name: "scalar in comparison",
+			s: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).Select(Max("OrderId")).Where(C("ItemId").EQ(3)).AsSubquery("sub")
+				return NewSelector[Order](db).Select(C("Id")).Where(C("UsingCol1").EQ("a"), C("Id").GT(sub))
+			}(),
+			wantQuery: Query{
+				SQL:  "SELECT `id` FROM `order` WHERE (`using_col1`=?) AND (`id`>(SELECT MAX(`order_id`) FROM `order_detail` WHERE `item_id`=?));",
+				Args: []any{"a", 3},
+			},
+		},
+		{
+			name: "scalar in select list",
+			s: func() QueryBuilder {
+				cnt := NewSelector[OrderDetail](db).Select(Count("ItemId")).Where(C("ItemId").GT(1)).AsSubquery("item_cnt")
+				return NewSelector[Order](db).Select(C("Id"), cnt).Where(C("Id").LT(100))
+			}(),
+			wantQuery: Query{
+				SQL:  "SELECT `id`,(SELECT COUNT(`item_id`) FROM `order_detail` WHERE `item_id`>?) AS `item_cnt` FROM `order` WHERE `id`<?;",
+				Args: []any{1, 100},
+			},
+		},
+		{
+			name: "same subquery twice",
+			s: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).Select(Max("OrderId")).Where(C("ItemId").EQ(3)).AsSubquery("max_id")
+				return NewSelector[Order](db).Select(C("Id"), sub).Where(C("Id").LT(sub))
+			}(),
+			wantQuery: Query{
+				SQL:  "SELECT `id`,(SELECT MAX(`order_id`) FROM `order_detail` WHERE `item_id`=?) AS `max_id` FROM `order` WHERE `id`<(SELECT MAX(`order_id`) FROM `order_detail` WHERE `item_id`=?);",
+				Args: []any{3, 3},
+			},
+		},
 		{
 			name: "all",
 			s: func() QueryBuilder {
@@ -1805,3 +1989,496 @@ func TestSelector_Subquery(t *testing.T) {
 		})
 	}
 }
+
+func TestSelector_Lock(t *testing.T) {
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
+	sqliteDB := memoryDB()
+	pgDB := postgresDB(t)
+	testCases := []CommonTestCase{
+		{
+			name:     "for update",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Where(C("Id").EQ(1)).ForUpdate(),
+			wantSql:  "SELECT `id` FROM `test_model` WHERE `id`=? FOR UPDATE;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "for share nowait",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).Limit(10).ForShare().NoWait(),
+			wantSql:  "SELECT `id` FROM `test_model` LIMIT ? FOR SHARE NOWAIT;",
+			wantArgs: []interface{}{10},
+		},
+		{
+			name:     "postgres skip locked",
+			builder:  NewSelector[TestModel](pgDB).Select(C("Id")).Where(C("Age").GT(18)).Limit(1).ForUpdate().SkipLocked(),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "age">$1 LIMIT $2 FOR UPDATE SKIP LOCKED;`,
+			wantArgs: []interface{}{18, 1},
+		},
+		{
+			name:    "nowait without lock",
+			builder: NewSelector[TestModel](mysqlDB).NoWait(),
+			wantErr: errs.ErrLockWaitWithoutLock,
+		},
+		{
+			name:    "sqlite",
+			builder: NewSelector[TestModel](sqliteDB).ForUpdate(),
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "FOR UPDATE"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestSelector_Hints(t *testing.T) {
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name: "optimizer hints",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				Hints("MAX_EXECUTION_TIME(1000)", "NO_RANGE_OPTIMIZATION(test_model)").Distinct(),
+			wantSql: "SELECT /*+ MAX_EXECUTION_TIME(1000) NO_RANGE_OPTIMIZATION(test_model) */ DISTINCT `id` FROM `test_model`;",
+		},
+		{
+			name: "force index",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				ForceIndex("idx_age").IgnoreIndex("idx_first_name", "idx_last_name").Where(C("Age").GT(18)),
+			wantSql:  "SELECT `id` FROM `test_model` FORCE INDEX (`idx_age`) IGNORE INDEX (`idx_first_name`,`idx_last_name`) WHERE `age`>?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "use index with alias",
+			builder: NewSelector[TestModel](mysqlDB).From(TableOf(&TestModel{}, "t1")).
+				Hints("MAX_EXECUTION_TIME(1000)").UseIndex("idx_age"),
+			wantSql: "SELECT /*+ MAX_EXECUTION_TIME(1000) */ `id`,`first_name`,`age`,`last_name` FROM `test_model` AS `t1` USE INDEX (`idx_age`);",
+		},
+		{
+			name: "hint closing comment",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id")).
+				Hints("MAX_EXECUTION_TIME(1000) */ 1; DROP TABLE `test_model`; /*"),
+			wantErr: errs.NewInvalidHintError("MAX_EXECUTION_TIME(1000) */ 1; DROP TABLE `test_model`; /*"),
+		},
+		{
+			name:    "without index",
+			builder: NewSelector[TestModel](mysqlDB).UseIndex(),
+			wantErr: errs.ErrIndexHintWithoutIndex,
+		},
+		{
+			name: "join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewSelector[TestModel](mysqlDB).Select(t1.C("Id")).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age")))).ForceIndex("idx_age")
+			}(),
+			wantErr: errs.ErrIndexHintOnlySingleTable,
+		},
+		{
+			name:    "sqlite",
+			builder: NewSelector[TestModel](memoryDB()).ForceIndex("idx_age"),
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "FORCE INDEX"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+type legacyUser struct {
+	Id   int64  `eorm:"primary_key,column=usr_id"`
+	Name string `eorm:"column=usr_nm"`
+}
+
+func (legacyUser) TableName() string {
+	return "usr_tab"
+}
+
+func TestSelector_ColumnAndTableName(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := OpenDS("mysql", single.NewDB(mockDB))
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `usr_id`,`usr_nm` FROM `usr_tab` WHERE `usr_nm`=? LIMIT ?;").
+		WithArgs("Tom", 1).
+		WillReturnRows(mock.NewRows([]string{"usr_id", "usr_nm"}).AddRow(1, "Tom"))
+	res, err := NewSelector[legacyUser](db).Where(C("Name").EQ("Tom")).Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &legacyUser{Id: 1, Name: "Tom"}, res)
+
+	mock.ExpectExec("UPDATE `usr_tab` SET `usr_nm`=? WHERE `usr_id`=?;").
+		WithArgs("Jerry", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = NewUpdater[legacyUser](db).Update(&legacyUser{Name: "Jerry"}).
+		Set(C("Name")).Where(C("Id").EQ(1)).Exec(context.Background()).Err()
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_PointerCombination(t *testing.T) {
+	type BaseEntity struct {
+		CreateTime uint64
+		UpdateTime uint64
+	}
+	type User struct {
+		*BaseEntity
+		Id   int64 `eorm:"primary_key"`
+		Name string
+	}
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer func() { _ = mockDB.Close() }()
+	db, err := OpenDS("mysql", single.NewDB(mockDB))
+	require.NoError(t, err)
+
+	// 扫描的时候会创建组合的指针
+	mock.ExpectQuery("SELECT `create_time`,`update_time`,`id`,`name` FROM `user` WHERE `id`=? LIMIT ?;").
+		WithArgs(1, 1).
+		WillReturnRows(mock.NewRows([]string{"create_time", "update_time", "id", "name"}).AddRow(100, 200, 1, "Tom"))
+	res, err := NewSelector[User](db).Where(C("Id").EQ(1)).Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &User{BaseEntity: &BaseEntity{CreateTime: 100, UpdateTime: 200}, Id: 1, Name: "Tom"}, res)
+
+	// 组合的指针是 nil 的时候，插入和更新都当作 NULL
+	mock.ExpectExec("INSERT INTO `user`(`create_time`,`update_time`,`id`,`name`) VALUES(?,?,?,?);").
+		WithArgs(nil, nil, 2, "Jerry").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = NewInserter[User](db).Values(&User{Id: 2, Name: "Jerry"}).Exec(context.Background()).Err()
+	require.NoError(t, err)
+
+	mock.ExpectExec("UPDATE `user` SET `create_time`=?,`name`=? WHERE `id`=?;").
+		WithArgs(nil, "Jerry", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = NewUpdater[User](db).Update(&User{Name: "Jerry"}).
+		Set(C("CreateTime"), C("Name")).Where(C("Id").EQ(2)).Exec(context.Background()).Err()
+	require.NoError(t, err)
+
+	mock.ExpectExec("UPDATE `user` SET `id`=?,`name`=? WHERE `id`=?;").
+		WithArgs(2, "Jerry", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = NewUpdater[User](db).Update(&User{Id: 2, Name: "Jerry"}).SkipNilValue().
+		Where(C("Id").EQ(2)).Exec(context.Background()).Err()
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_With(t *testing.T) {
+	db := memoryDB()
+	type OrderDetail struct {
+		OrderId int
+		ItemId  int
+	}
+	testCases := []CommonTestCase{
+		{
+			name: "from cte",
+			builder: func() QueryBuilder {
+				adult := NewSelector[TestModel](db).Where(C("Age").GT(18)).AsSubquery("adult")
+				return NewSelector[TestModel](db).With(adult).From(adult).
+					Where(adult.C("Id").LT(100))
+			}(),
+			wantSql:  "WITH `adult` AS (SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` WHERE `age`>?) SELECT `id`,`first_name`,`age`,`last_name` FROM `adult` WHERE `adult`.`id`<?;",
+			wantArgs: []interface{}{18, 100},
+		},
+		{
+			name: "join cte",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				od := NewSelector[OrderDetail](db).Select(C("OrderId")).Where(C("ItemId").EQ(3)).AsSubquery("od")
+				adult := NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).AsSubquery("adult")
+				return NewSelector[TestModel](db).With(od, adult).Select(t1.C("FirstName")).
+					From(t1.Join(od).On(t1.C("Id").EQ(od.C("OrderId"))).Join(adult).On(t1.C("Id").EQ(adult.C("Id")))).
+					Where(t1.C("FirstName").EQ("Tom"))
+			}(),
+			wantSql:  "WITH `od` AS (SELECT `order_id` FROM `order_detail` WHERE `item_id`=?),`adult` AS (SELECT `id` FROM `test_model` WHERE `age`>?) SELECT `t1`.`first_name` FROM ((`test_model` AS `t1` JOIN `od` ON `t1`.`id`=`od`.`order_id`) JOIN `adult` ON `t1`.`id`=`adult`.`id`) WHERE `t1`.`first_name`=?;",
+			wantArgs: []interface{}{3, 18, "Tom"},
+		},
+		{
+			name: "recursive",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				tree := CTEOf(&TestModel{}, "tree")
+				anchor := NewSelector[TestModel](db).Select(C("Id")).Where(C("Id").EQ(1))
+				cte := anchor.UnionAll(NewSelector[TestModel](db).Select(t1.C("Id")).
+					From(t1.Join(tree).On(t1.C("Age").EQ(tree.C("Id"))))).AsSubquery("tree")
+				return NewSelector[TestModel](db).WithRecursive(cte).Select(C("Id")).From(cte)
+			}(),
+			wantSql:  "WITH RECURSIVE `tree` AS (SELECT `id` FROM `test_model` WHERE `id`=? UNION ALL SELECT `t1`.`id` FROM (`test_model` AS `t1` JOIN `tree` ON `t1`.`age`=`tree`.`id`)) SELECT `id` FROM `tree`;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "subquery not declared",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel](db).Select(C("Id")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(C("Id")).From(sub)
+			}(),
+			wantSql: "SELECT `id` FROM (SELECT `id` FROM `test_model`) AS `sub`;",
+		},
+		{
+			name: "without name",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel](db).AsSubquery("")
+				return NewSelector[TestModel](db).With(sub)
+			}(),
+			wantErr: errs.ErrCTEWithoutName,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestSelector_OrderByAndGroupBy(t *testing.T) {
+	db := memoryDB()
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
+	pgDB := postgresDB(t)
+	testCases := []CommonTestCase{
+		{
+			name: "order by aggregate",
+			builder: NewSelector[TestModel](db).Select(C("FirstName"), Count("Id")).
+				GroupBy("FirstName").OrderBy(Count("Id").DESC(), ASC("FirstName")),
+			wantSql: "SELECT `first_name`,COUNT(`id`) FROM `test_model` GROUP BY `first_name` ORDER BY COUNT(`id`) DESC,`first_name` ASC;",
+		},
+		{
+			name: "order by and group by alias",
+			builder: NewSelector[TestModel](db).Select(Length(C("FirstName")).As("len"), Count("Id").As("cnt")).
+				GroupBy("len").OrderBy(DESC("cnt"), C("len").ASC()),
+			wantSql: "SELECT LENGTH(`first_name`) AS `len`,COUNT(`id`) AS `cnt` FROM `test_model` GROUP BY `len` ORDER BY `cnt` DESC,`len` ASC;",
+		},
+		{
+			name: "having before order by",
+			builder: NewSelector[TestModel](db).Select(C("FirstName"), Avg("Age").As("avg_age")).
+				GroupBy("FirstName").Having(Avg("Age").GT(18)).OrderBy(DESC("avg_age")),
+			wantSql:  "SELECT `first_name`,AVG(`age`) AS `avg_age` FROM `test_model` GROUP BY `first_name` HAVING AVG(`age`)>? ORDER BY `avg_age` DESC;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "qualified columns",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewSelector[TestModel](db).Select(t1.C("Id"), t2.Count("Id")).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age")))).
+					GroupByExprs(t1.C("Id")).OrderBy(t2.Count("Id").DESC(), t1.C("Id").ASC())
+			}(),
+			wantSql: "SELECT `t1`.`id`,COUNT(`t2`.`id`) FROM (`test_model` AS `t1` JOIN `test_model` AS `t2` ON `t1`.`id`=`t2`.`age`) GROUP BY `t1`.`id` ORDER BY COUNT(`t2`.`id`) DESC,`t1`.`id` ASC;",
+		},
+		{
+			name:    "nulls first",
+			builder: NewSelector[TestModel](db).OrderBy(ASC("Age").NullsFirst(), DESC("Id").NullsLast()),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` ASC NULLS FIRST,`id` DESC NULLS LAST;",
+		},
+		{
+			name:    "postgres nulls last",
+			builder: NewSelector[TestModel](pgDB).OrderBy(Max("Age").DESC().NullsLast()),
+			wantSql: `SELECT "id","first_name","age","last_name" FROM "test_model" ORDER BY MAX("age") DESC NULLS LAST;`,
+		},
+		{
+			name:    "mysql nulls",
+			builder: NewSelector[TestModel](mysqlDB).OrderBy(ASC("Age").NullsLast(), DESC("Id").NullsFirst()),
+			wantSql: "SELECT `id`,`first_name`,`age`,`last_name` FROM `test_model` ORDER BY `age` IS NULL ASC,`age` ASC,`id` IS NULL DESC,`id` DESC;",
+		},
+		{
+			name:     "order by math expression",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).OrderBy(C("Age").Add(1).DESC()),
+			wantSql:  "SELECT `id` FROM `test_model` ORDER BY (`age`+?) DESC;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "order by any expression",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				OrderBy(OrderByExpr(C("Age").Add(1).Add(2)), OrderByExpr(Raw("RANDOM()")).DESC()),
+			wantSql:  "SELECT `id` FROM `test_model` ORDER BY ((`age`+?)+?) ASC,RANDOM() DESC;",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "order by window",
+			builder: NewSelector[TestModel](db).Select(C("Id")).
+				OrderBy(RowNumber().PartitionBy("FirstName").OrderBy(ASC("Age")).DESC()),
+			wantSql: "SELECT `id` FROM `test_model` ORDER BY ROW_NUMBER() OVER (PARTITION BY `first_name` ORDER BY `age` ASC) DESC;",
+		},
+		{
+			name: "order by subquery",
+			builder: func() QueryBuilder {
+				sub := NewSelector[TestModel](db).Select(Max("Age")).Where(C("Age").LT(18)).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(C("Id")).OrderBy(sub.ASC())
+			}(),
+			wantSql:  "SELECT `id` FROM `test_model` ORDER BY (SELECT MAX(`age`) FROM `test_model` WHERE `age`<?) ASC;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name:     "mysql order by math expression nulls",
+			builder:  NewSelector[TestModel](mysqlDB).Select(C("Id")).OrderBy(C("Age").Multi(2).ASC().NullsLast()),
+			wantSql:  "SELECT `id` FROM `test_model` ORDER BY (`age`*?) IS NULL ASC,(`age`*?) ASC;",
+			wantArgs: []interface{}{2, 2},
+		},
+		{
+			name:    "invalid alias",
+			builder: NewSelector[TestModel](db).Select(Count("Id").As("cnt")).OrderBy(DESC("Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid group by alias",
+			builder: NewSelector[TestModel](db).Select(Count("Id").As("cnt")).GroupBy("Invalid"),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid aggregate",
+			builder: NewSelector[TestModel](db).OrderBy(Count("Invalid").ASC()),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestSelector_PostgreSQL(t *testing.T) {
+	db := postgresDB(t)
+	type OrderDetail struct {
+		OrderId int
+		ItemId  int
+	}
+	testCases := []CommonTestCase{
+		{
+			name:     "where",
+			builder:  NewSelector[TestModel](db).Where(C("Id").EQ(10).And(C("Age").GT(18))),
+			wantSql:  `SELECT "id","first_name","age","last_name" FROM "test_model" WHERE ("id"=$1) AND ("age">$2);`,
+			wantArgs: []interface{}{10, 18},
+		},
+		{
+			name:     "in",
+			builder:  NewSelector[TestModel](db).Select(C("Id").As("my_id")).Where(C("Id").In(1, 2, 3)),
+			wantSql:  `SELECT "id" AS "my_id" FROM "test_model" WHERE "id" IN ($1,$2,$3);`,
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name:     "offset",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Offset(10),
+			wantSql:  `SELECT "id" FROM "test_model" OFFSET $1;`,
+			wantArgs: []interface{}{10},
+		},
+		{
+			name:     "limit offset",
+			builder:  NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).Limit(10).Offset(20),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE "age">$1 LIMIT $2 OFFSET $3;`,
+			wantArgs: []interface{}{18, 10, 20},
+		},
+		{
+			name: "subquery",
+			builder: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).Select(C("OrderId")).Where(C("ItemId").GT(3)).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18).And(C("Id").In(sub))).Limit(1)
+			}(),
+			wantSql:  `SELECT "id" FROM "test_model" WHERE ("age">$1) AND ("id" IN (SELECT "order_id" FROM "order_detail" WHERE "item_id">$2)) LIMIT $3;`,
+			wantArgs: []interface{}{18, 3, 1},
+		},
+		{
+			name: "all columns",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&OrderDetail{}, "t2")
+				return NewSelector[TestModel](db).Select(t1.AllColumns()).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("OrderId"))))
+			}(),
+			wantSql: `SELECT "t1".* FROM ("test_model" AS "t1" JOIN "order_detail" AS "t2" ON "t1"."id"="t2"."order_id");`,
+		},
+		{
+			name: "scalar subquery",
+			builder: func() QueryBuilder {
+				cnt := NewSelector[OrderDetail](db).Select(Count("ItemId")).Where(C("ItemId").GT(1)).AsSubquery("cnt")
+				sub := NewSelector[OrderDetail](db).Select(C("OrderId")).Where(C("ItemId").LT(5)).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(C("Id"), cnt).Where(C("Id").NotIn(sub), C("Age").GT(18))
+			}(),
+			wantSql:  `SELECT "id",(SELECT COUNT("item_id") FROM "order_detail" WHERE "item_id">$1) AS "cnt" FROM "test_model" WHERE ("id" NOT IN (SELECT "order_id" FROM "order_detail" WHERE "item_id"<$2)) AND ("age">$3);`,
+			wantArgs: []interface{}{1, 5, 18},
+		},
+		{
+			name: "cte",
+			builder: func() QueryBuilder {
+				adult := NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").GT(18)).AsSubquery("adult")
+				return NewSelector[TestModel](db).With(adult).Select(C("Id")).From(adult).
+					Where(adult.C("Id").LT(100)).Limit(10)
+			}(),
+			wantSql:  `WITH "adult" AS (SELECT "id" FROM "test_model" WHERE "age">$1) SELECT "id" FROM "adult" WHERE "adult"."id"<$2 LIMIT $3;`,
+			wantArgs: []interface{}{18, 100, 10},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestSelector_PostgresPlaceholder(t *testing.T) {
+	pgDB := postgresDB(t)
+
+	// 作为子查询之后，单独使用的时候参数依旧从 $1 开始编号
+	inner := NewSelector[TestModel](pgDB).Select(C("Id")).Where(C("Age").GT(18))
+	outer := NewSelector[TestModel](pgDB).Where(C("FirstName").EQ("Tom"), C("Id").In(inner.AsSubquery("sub")))
+	q, err := outer.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "id","first_name","age","last_name" FROM "test_model" WHERE ("first_name"=$1) AND ("id" IN (SELECT "id" FROM "test_model" WHERE "age">$2));`, q.SQL)
+	assert.Equal(t, []interface{}{"Tom", 18}, q.Args)
+	q, err = inner.Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "id" FROM "test_model" WHERE "age">$1;`, q.SQL)
+	assert.Equal(t, []interface{}{18}, q.Args)
+
+	// RawExpr 中的 ? 会按照顺序替换为 $n
+	q, err = NewSelector[TestModel](pgDB).Select(C("Id")).
+		Where(C("Id").EQ(1), Raw("age > ? AND age < ?", 18, 30).AsPredicate()).Build()
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "id" FROM "test_model" WHERE ("id"=$1) AND (age > $2 AND age < $3);`, q.SQL)
+	assert.Equal(t, []interface{}{1, 18, 30}, q.Args)
+}
@@ -66,7 +66,10 @@ func (b *shardingBuilder) findDstByPredicate(ctx context.Context, pre Predicate)
 		return b.mergeOR(left, right), nil
 	case opIn:
 		col := pre.left.(Column)
-		right := pre.right.(values)
+		right, ok := pre.right.(values)
+		if !ok {
+			return sharding.EmptyResp, errs.ErrUnsupportedTooComplexQuery
+		}
 		var results []sharding.Response
 		for _, val := range right.data {
 			res, err := b.meta.ShardingAlgorithm.Sharding(ctx,
@@ -86,6 +89,12 @@ func (b *shardingBuilder) findDstByPredicate(ctx context.Context, pre Predicate)
 	case opNotIN:
 		return b.meta.ShardingAlgorithm.Sharding(ctx,
 			sharding.Request{Op: opNotIN, SkValues: map[string]any{}})
+	case opBetween, opNotBetween:
+		return b.findDstByBetween(ctx, pre)
+	case opIsNull, opNotNull:
+		return sharding.Response{
+			Dsts: b.meta.ShardingAlgorithm.Broadcast(ctx),
+		}, nil
 	case opEQ, opGT, opLT, opGTEQ, opLTEQ, opNEQ:
 		col, isCol := pre.left.(Column)
 		right, isVals := pre.right.(valueExpr)
@@ -99,6 +108,38 @@ func (b *shardingBuilder) findDstByPredicate(ctx context.Context, pre Predicate)
 	}
 }
 
+// findDstByBetween 将 BETWEEN 拆成 >= AND <=，NOT BETWEEN 拆成 < OR >
+func (b *shardingBuilder) findDstByBetween(ctx context.Context, pre Predicate) (sharding.Response, error) {
+	col, isCol := pre.left.(Column)
+	rng, isRange := pre.right.(binaryExpr)
+	if !isCol || !isRange {
+		return sharding.EmptyResp, errs.ErrUnsupportedTooComplexQuery
+	}
+	start, isStartVal := rng.left.(valueExpr)
+	end, isEndVal := rng.right.(valueExpr)
+	if !isStartVal || !isEndVal {
+		return sharding.EmptyResp, errs.ErrUnsupportedTooComplexQuery
+	}
+	startOp, endOp := opGTEQ, opLTEQ
+	if pre.op == opNotBetween {
+		startOp, endOp = opLT, opGT
+	}
+	left, err := b.meta.ShardingAlgorithm.Sharding(ctx,
+		sharding.Request{Op: startOp, SkValues: map[string]any{col.name: start.val}})
+	if err != nil {
+		return sharding.EmptyResp, err
+	}
+	right, err := b.meta.ShardingAlgorithm.Sharding(ctx,
+		sharding.Request{Op: endOp, SkValues: map[string]any{col.name: end.val}})
+	if err != nil {
+		return sharding.EmptyResp, err
+	}
+	if pre.op == opNotBetween {
+		return b.mergeOR(left, right), nil
+	}
+	return b.mergeAnd(left, right), nil
+}
+
 func (b *shardingBuilder) negatePredicate(pre Predicate) (Predicate, error) {
 	switch pre.op {
 	case opAnd:
@@ -18,11 +18,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"reflect"
 	"sync"
 
 	"github.com/ecodeclub/ekit/mapx"
 
+	"github.com/ecodeclub/eorm/internal/dialect"
 	"github.com/ecodeclub/eorm/internal/errs"
 	"github.com/ecodeclub/eorm/internal/model"
 	"github.com/ecodeclub/eorm/internal/sharding"
@@ -40,6 +40,20 @@ type ShardingInserter[T any] struct {
 }
 
 func (si *ShardingInserter[T]) Build(ctx context.Context) ([]sharding.Query, error) {
+	groups, err := si.buildGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ansQuery := make([]sharding.Query, 0, len(groups))
+	for _, g := range groups {
+		ansQuery = append(ansQuery, g...)
+	}
+	return ansQuery, nil
+}
+
+// buildGroups 针对每一个目标表生成一组 insert 语句
+// 开启了分批插入的时候，一组里面会有多个语句，否则只有一个
+func (si *ShardingInserter[T]) buildGroups(ctx context.Context) ([][]sharding.Query, error) {
 	defer bytebufferpool.Put(si.buffer)
 	var err error
 	if len(si.values) == 0 {
@@ -57,6 +71,9 @@ func (si *ShardingInserter[T]) Build(ctx context.Context) ([]sharding.Query, err
 	if err := si.checkColumns(colMetaData, skNames); err != nil {
 		return nil, err
 	}
+	if err := si.checkUpsert(skNames); err != nil {
+		return nil, err
+	}
 
 	// ds-db => 目标表
 	//dsDBMap, err := mapx.NewTreeMap[key, *mapx.TreeMap[key, []*T]](compareDSDB)
@@ -79,31 +96,53 @@ func (si *ShardingInserter[T]) Build(ctx context.Context) ([]sharding.Query, err
 		}
 	}
 
-	// 针对每一个目标库，生成一个 insert 语句
+	// 针对每一个目标表，生成 insert 语句
 	//dsDBKeys := dsDBMap.Keys()
 	dsts := dsDBTabMap.Keys()
-	ansQuery := make([]sharding.Query, 0, len(dsts))
+	fixed, err := upsertArgs(si.core, si.meta, si.upsert)
+	if err != nil {
+		return nil, err
+	}
+	size, err := si.chunkSize(len(colMetaData), fixed)
+	if err != nil {
+		return nil, err
+	}
+	ansGroups := make([][]sharding.Query, 0, len(dsts))
 	for _, dst := range dsts {
 		vals, _ := dsDBTabMap.Get(dst)
-		err = si.buildQuery(dst.DB, dst.Table, colMetaData, vals)
-		if err != nil {
-			return nil, err
+		chunk := len(vals)
+		if size > 0 {
+			chunk = size
 		}
-		ansQuery = append(ansQuery, sharding.Query{
-			SQL:        si.buffer.String(),
-			Args:       si.args,
-			DB:         dst.DB,
-			Datasource: dst.Name,
-		})
-		si.buffer.Reset()
-		si.args = []any{}
+		group := make([]sharding.Query, 0, (len(vals)+chunk-1)/chunk)
+		for start := 0; start < len(vals); start += chunk {
+			end := start + chunk
+			if end > len(vals) {
+				end = len(vals)
+			}
+			err = si.buildQuery(dst.DB, dst.Table, colMetaData, vals[start:end])
+			if err != nil {
+				return nil, err
+			}
+			group = append(group, sharding.Query{
+				SQL:        si.buffer.String(),
+				Args:       si.args,
+				DB:         dst.DB,
+				Datasource: dst.Name,
+			})
+			si.buffer.Reset()
+			si.args = []any{}
+		}
+		ansGroups = append(ansGroups, group)
 	}
-	return ansQuery, nil
+	return ansGroups, nil
 }
 
 func (si *ShardingInserter[T]) buildQuery(db, table string, colMetas []*model.ColumnMeta, values []*T) error {
 	var err error
-	si.writeString("INSERT INTO ")
+	if err = si.dialect.BuildInsert(dialectWriter{b: &si.builder}, si.mode); err != nil {
+		return err
+	}
 	si.quote(db)
 	si.writeByte('.')
 	si.quote(table)
@@ -114,6 +153,7 @@ func (si *ShardingInserter[T]) buildQuery(db, table string, colMetas []*model.Co
 	}
 	si.writeString(")")
 	si.writeString(" VALUES")
+	now := si.now()
 	for index, val := range values {
 		if index > 0 {
 			si.comma()
@@ -125,13 +165,19 @@ func (si *ShardingInserter[T]) buildQuery(db, table string, colMetas []*model.Co
 			if err != nil {
 				return err
 			}
-			si.parameter(fdVal.Interface())
+			si.parameter(insertValue(v, fdVal, now))
 			if j != len(colMetas)-1 {
 				si.comma()
 			}
 		}
 		si.writeString(")")
 	}
+	si.dialect.BuildInsertSuffix(dialectWriter{b: &si.builder}, si.mode)
+	if si.upsert != nil {
+		if err = si.buildUpsert(si.upsert); err != nil {
+			return err
+		}
+	}
 	si.end()
 	return nil
 }
@@ -150,12 +196,52 @@ func (*ShardingInserter[T]) checkColumns(colMetas []*model.ColumnMeta, sks []str
 	return nil
 }
 
+// checkUpsert 冲突之后不允许更新 sharding key，否则数据会落在错误的表里面；
+// 同时 upsert 也不能和 INSERT IGNORE 或者 REPLACE 一起使用
+func (si *ShardingInserter[T]) checkUpsert(sks []string) error {
+	if si.upsert == nil {
+		return nil
+	}
+	if si.mode != dialect.InsertModeDefault {
+		return errs.ErrInsertModeWithUpsert
+	}
+	for _, assign := range si.upsert.assigns {
+		var names []string
+		switch a := assign.(type) {
+		case Column:
+			names = []string{a.name}
+		case columns:
+			names = a.cs
+		case Assignment:
+			if c, ok := a.left.(Column); ok {
+				names = []string{c.name}
+			}
+		}
+		for _, name := range names {
+			for _, sk := range sks {
+				if name == sk {
+					return errs.NewErrUpdateShardingKeyUnsupported(name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (si *ShardingInserter[T]) findDst(ctx context.Context, val *T) (sharding.Response, error) {
 	sks := si.meta.ShardingAlgorithm.ShardingKeys()
 	skValues := make(map[string]any)
+	refVal := si.valCreator.NewPrimitiveValue(val, si.meta)
 	for _, sk := range sks {
-		refVal := reflect.ValueOf(val).Elem().FieldByName(sk).Interface()
-		skValues[sk] = refVal
+		fd, err := refVal.Field(sk)
+		if err != nil {
+			return sharding.EmptyResp, err
+		}
+		// sharding key 在 nil 的指针组合里面，没有办法路由
+		if !fd.CanAddr() {
+			return sharding.EmptyResp, errs.ErrInsertShardingKeyNotFound
+		}
+		skValues[sk] = fd.Interface()
 	}
 	return si.meta.ShardingAlgorithm.Sharding(ctx, sharding.Request{
 		Op:       opEQ,
@@ -209,6 +295,47 @@ func (si *ShardingInserter[T]) IgnorePK() *ShardingInserter[T] {
 	return si
 }
 
+// Ignore 冲突的时候忽略该行
+func (si *ShardingInserter[T]) Ignore() *ShardingInserter[T] {
+	si.mode = dialect.InsertModeIgnore
+	return si
+}
+
+// Replace 冲突的时候使用新的行替换旧的行
+func (si *ShardingInserter[T]) Replace() *ShardingInserter[T] {
+	si.mode = dialect.InsertModeReplace
+	return si
+}
+
+// OnDuplicateKey 开始构造冲突之后的更新部分，会作用于每一个目标表
+func (si *ShardingInserter[T]) OnDuplicateKey() *OnDuplicateKeyBuilder[*ShardingInserter[T]] {
+	return &OnDuplicateKeyBuilder[*ShardingInserter[T]]{
+		inserter: si,
+		attr:     &si.inserterBuilderAttribute,
+	}
+}
+
+// OnConflict 开始构造冲突之后的更新部分，cs 是冲突列的字段名
+func (si *ShardingInserter[T]) OnConflict(cs ...string) *OnDuplicateKeyBuilder[*ShardingInserter[T]] {
+	return &OnDuplicateKeyBuilder[*ShardingInserter[T]]{
+		inserter:        si,
+		attr:            &si.inserterBuilderAttribute,
+		conflictColumns: cs,
+	}
+}
+
+// Batch 开启分批插入，每一个目标表上的数据会被切分为多批，每一批最多 size 行
+func (si *ShardingInserter[T]) Batch(size int) *ShardingInserter[T] {
+	si.batchSize = size
+	return si
+}
+
+// BatchByArgs 开启分批插入，每一批的参数个数不超过 limit
+func (si *ShardingInserter[T]) BatchByArgs(limit int) *ShardingInserter[T] {
+	si.batchArgs = limit
+	return si
+}
+
 func NewShardingInsert[T any](db Session) *ShardingInserter[T] {
 	b := shardingInserterBuilder{}
 	b.core = db.getCore()
@@ -221,23 +348,32 @@ func NewShardingInsert[T any](db Session) *ShardingInserter[T] {
 }
 
 func (si *ShardingInserter[T]) Exec(ctx context.Context) sharding.Result {
-	qs, err := si.Build(ctx)
+	groups, err := si.buildGroups(ctx)
 	if err != nil {
 		return sharding.NewResult(nil, err)
 	}
-	errList := make([]error, len(qs))
-	resList := make([]sql.Result, len(qs))
+	errList := make([]error, len(groups))
+	resList := make([]sql.Result, 0, len(groups))
 	var wg sync.WaitGroup
-	wg.Add(len(qs))
-	for idx, q := range qs {
-		go func(idx int, q Query) {
+	wg.Add(len(groups))
+	// 不同的目标表并发执行，同一个目标表上的多个批次按照顺序执行
+	for idx, qs := range groups {
+		go func(idx int, qs []Query) {
 			defer wg.Done()
-			res, er := si.db.execContext(ctx, q)
-			si.lock.Lock()
-			errList[idx] = er
-			resList[idx] = res
-			si.lock.Unlock()
-		}(idx, q)
+			for _, q := range qs {
+				res, er := si.db.execContext(ctx, si.commenter.comment(ctx, q))
+				si.lock.Lock()
+				if er != nil {
+					errList[idx] = er
+				} else {
+					resList = append(resList, res)
+				}
+				si.lock.Unlock()
+				if er != nil {
+					return
+				}
+			}
+		}(idx, qs)
 	}
 	wg.Wait()
 	shardingRes := sharding.NewResult(resList, multierr.Combine(errList...))
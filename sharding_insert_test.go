@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/ecodeclub/eorm/internal/datasource"
@@ -128,6 +129,126 @@ func TestShardingInsert_Build(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "插入多个元素并且冲突之后更新",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+				{UserId: 2, OrderId: 2, Content: "2", Account: 2.0},
+			}).OnConflict("UserId").Update(Columns("Content"), Assign("Account", C("Account").Add(1))),
+			wantQs: []sharding.Query{
+				{
+					SQL:        fmt.Sprintf("INSERT INTO %s.%s(`user_id`,`order_id`,`content`,`account`) VALUES(?,?,?,?) ON CONFLICT(`user_id`) DO UPDATE SET `content`=excluded.`content`,`account`=(`account`+?);", "`order_db_0`", "`order_tab_2`"),
+					Args:       []any{2, int64(2), "2", 2.0, 1},
+					DB:         "order_db_0",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+				{
+					SQL:        fmt.Sprintf("INSERT INTO %s.%s(`user_id`,`order_id`,`content`,`account`) VALUES(?,?,?,?) ON CONFLICT(`user_id`) DO UPDATE SET `content`=excluded.`content`,`account`=(`account`+?);", "`order_db_1`", "`order_tab_1`"),
+					Args:       []any{1, int64(1), "1", 1.0, 1},
+					DB:         "order_db_1",
+					Datasource: "1.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "插入时忽略冲突",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+			}).Ignore(),
+			wantQs: []sharding.Query{
+				{
+					SQL:        fmt.Sprintf("INSERT OR IGNORE INTO %s.%s(`user_id`,`order_id`,`content`,`account`) VALUES(?,?,?,?);", "`order_db_1`", "`order_tab_1`"),
+					Args:       []any{1, int64(1), "1", 1.0},
+					DB:         "order_db_1",
+					Datasource: "1.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "插入时替换冲突的行",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+			}).Replace(),
+			wantQs: []sharding.Query{
+				{
+					SQL:        fmt.Sprintf("INSERT OR REPLACE INTO %s.%s(`user_id`,`order_id`,`content`,`account`) VALUES(?,?,?,?);", "`order_db_1`", "`order_tab_1`"),
+					Args:       []any{1, int64(1), "1", 1.0},
+					DB:         "order_db_1",
+					Datasource: "1.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "分批插入",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+				{UserId: 7, OrderId: 7, Content: "7", Account: 7.0},
+				{UserId: 13, OrderId: 13, Content: "13", Account: 13.0},
+			}).Batch(2),
+			wantQs: []sharding.Query{
+				{
+					SQL:        fmt.Sprintf("INSERT INTO %s.%s(`user_id`,`order_id`,`content`,`account`) VALUES(?,?,?,?),(?,?,?,?);", "`order_db_1`", "`order_tab_1`"),
+					Args:       []any{1, int64(1), "1", 1.0, 7, int64(7), "7", 7.0},
+					DB:         "order_db_1",
+					Datasource: "1.db.cluster.company.com:3306",
+				},
+				{
+					SQL:        fmt.Sprintf("INSERT INTO %s.%s(`user_id`,`order_id`,`content`,`account`) VALUES(?,?,?,?);", "`order_db_1`", "`order_tab_1`"),
+					Args:       []any{13, int64(13), "13", 13.0},
+					DB:         "order_db_1",
+					Datasource: "1.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "按照参数个数分批插入",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+				{UserId: 7, OrderId: 7, Content: "7", Account: 7.0},
+			}).BatchByArgs(6),
+			wantQs: []sharding.Query{
+				{
+					SQL:        fmt.Sprintf("INSERT INTO %s.%s(`user_id`,`order_id`,`content`,`account`) VALUES(?,?,?,?);", "`order_db_1`", "`order_tab_1`"),
+					Args:       []any{1, int64(1), "1", 1.0},
+					DB:         "order_db_1",
+					Datasource: "1.db.cluster.company.com:3306",
+				},
+				{
+					SQL:        fmt.Sprintf("INSERT INTO %s.%s(`user_id`,`order_id`,`content`,`account`) VALUES(?,?,?,?);", "`order_db_1`", "`order_tab_1`"),
+					Args:       []any{7, int64(7), "7", 7.0},
+					DB:         "order_db_1",
+					Datasource: "1.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "冲突之后更新 sharding key",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+			}).OnDuplicateKey().Update(Columns("Content", "UserId")),
+			wantErr: errs.NewErrUpdateShardingKeyUnsupported("UserId"),
+		},
+		{
+			name: "参数个数放不下一行",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+			}).BatchByArgs(3),
+			wantErr: errs.ErrInvalidBatchSize,
+		},
+		{
+			name: "ignore 和 upsert 一起使用",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+			}).Ignore().OnDuplicateKey().Update(Columns("Content")),
+			wantErr: errs.ErrInsertModeWithUpsert,
+		},
+		{
+			name: "replace 和 upsert 一起使用",
+			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
+				{UserId: 1, OrderId: 1, Content: "1", Account: 1.0},
+			}).Replace().OnDuplicateKey().Update(Columns("Content")),
+			wantErr: errs.ErrInsertModeWithUpsert,
+		},
 		{
 			name: "插入多个元素, 但是不同的元素会被分配到同一个库",
 			builder: NewShardingInsert[OrderInsert](shardingDB).Values([]*OrderInsert{
@@ -326,6 +447,116 @@ func (s *ShardingInsertSuite) TestShardingInsert_Exec() {
 	}
 }
 
+func TestShardingInsert_AutoTime(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	r := model.NewMetaRegistry()
+	_, err := r.Register(&autoTimeModel{},
+		model.WithTableShardingAlgorithm(&hash.Hash{
+			ShardingKey:  "Id",
+			DBPattern:    &hash.Pattern{Name: "order_db_%d", Base: 2},
+			TablePattern: &hash.Pattern{Name: "order_tab_%d", Base: 3},
+			DsPattern:    &hash.Pattern{Name: "0.db.cluster.company.com:3306", NotSharding: true},
+		}))
+	require.NoError(t, err)
+	clusterDB := cluster.NewClusterDB(map[string]*masterslave.MasterSlavesDB{
+		"order_db_1": MasterSlavesMemoryDB(),
+	})
+	ds := map[string]datasource.DataSource{
+		"0.db.cluster.company.com:3306": clusterDB,
+	}
+	shardingDB, err := OpenDS("sqlite3", shardingsource.NewShardingDataSource(ds),
+		DBWithMetaRegistry(r), DBWithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	qs, err := NewShardingInsert[autoTimeModel](shardingDB).
+		Values([]*autoTimeModel{{Id: 1, Name: "Tom"}}).Build(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []sharding.Query{
+		{
+			SQL:        "INSERT INTO `order_db_1`.`order_tab_1`(`id`,`name`,`create_time`,`update_time`) VALUES(?,?,?,?);",
+			Args:       []any{1, "Tom", int64(1700000000), int64(1700000000123)},
+			DB:         "order_db_1",
+			Datasource: "0.db.cluster.company.com:3306",
+		},
+	}, qs)
+}
+
+type OrderInsertBase struct {
+	UserId int `eorm:"primary_key"`
+}
+
+type OrderInsertWithBase struct {
+	*OrderInsertBase
+	OrderId int64
+	Content string
+}
+
+func TestShardingInsert_PointerCombination(t *testing.T) {
+	r := model.NewMetaRegistry()
+	_, err := r.Register(&OrderInsertWithBase{},
+		model.WithTableShardingAlgorithm(&hash.Hash{
+			ShardingKey:  "UserId",
+			DBPattern:    &hash.Pattern{Name: "order_db_%d", Base: 2},
+			TablePattern: &hash.Pattern{Name: "order_tab_%d", Base: 3},
+			DsPattern:    &hash.Pattern{Name: "0.db.cluster.company.com:3306", NotSharding: true},
+		}))
+	require.NoError(t, err)
+	clusterDB := cluster.NewClusterDB(map[string]*masterslave.MasterSlavesDB{
+		"order_db_0": MasterSlavesMemoryDB(),
+		"order_db_1": MasterSlavesMemoryDB(),
+	})
+	ds := map[string]datasource.DataSource{
+		"0.db.cluster.company.com:3306": clusterDB,
+	}
+	shardingDB, err := OpenDS("sqlite3", shardingsource.NewShardingDataSource(ds), DBWithMetaRegistry(r))
+	require.NoError(t, err)
+	testCases := []struct {
+		name    string
+		values  []*OrderInsertWithBase
+		wantQs  []sharding.Query
+		wantErr error
+	}{
+		{
+			name: "sharding key in pointer combination",
+			values: []*OrderInsertWithBase{
+				{OrderInsertBase: &OrderInsertBase{UserId: 1}, OrderId: 1, Content: "1"},
+				{OrderInsertBase: &OrderInsertBase{UserId: 2}, OrderId: 2, Content: "2"},
+			},
+			wantQs: []sharding.Query{
+				{
+					SQL:        "INSERT INTO `order_db_1`.`order_tab_1`(`user_id`,`order_id`,`content`) VALUES(?,?,?);",
+					Args:       []any{1, int64(1), "1"},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+				{
+					SQL:        "INSERT INTO `order_db_0`.`order_tab_2`(`user_id`,`order_id`,`content`) VALUES(?,?,?);",
+					Args:       []any{2, int64(2), "2"},
+					DB:         "order_db_0",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "nil pointer combination",
+			values: []*OrderInsertWithBase{
+				{OrderId: 1, Content: "1"},
+			},
+			wantErr: errs.ErrInsertShardingKeyNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qs, err := NewShardingInsert[OrderInsertWithBase](shardingDB).Values(tc.values).Build(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.ElementsMatch(t, tc.wantQs, qs)
+		})
+	}
+}
+
 func TestShardingInsertSuite(t *testing.T) {
 	suite.Run(t, &ShardingInsertSuite{})
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/ecodeclub/eorm/internal/sharding"
 
+	"github.com/ecodeclub/eorm/internal/dialect"
 	"github.com/ecodeclub/eorm/internal/errs"
 	"github.com/valyala/bytebufferpool"
 )
@@ -31,6 +32,8 @@ type ShardingSelector[T any] struct {
 	table        *T
 	db           Session
 	queryFeature query.Feature
+	// rewriteIndex 用于将逻辑表上的索引名改写为物理表上的索引名
+	rewriteIndex func(index string, table string) string
 	// lock  sync.Mutex
 }
 
@@ -73,6 +76,11 @@ func (s *ShardingSelector[T]) Build(ctx context.Context) ([]sharding.Query, erro
 func (s *ShardingSelector[T]) buildQuery(db, tbl, ds string) (sharding.Query, error) {
 	var err error
 	s.writeString("SELECT ")
+	if len(s.hints) > 0 {
+		if err = s.buildHints(s.hints); err != nil {
+			return sharding.EmptyQuery, err
+		}
+	}
 	if len(s.columns) == 0 {
 		if err = s.buildAllColumns(); err != nil {
 			return sharding.EmptyQuery, err
@@ -87,12 +95,24 @@ func (s *ShardingSelector[T]) buildQuery(db, tbl, ds string) (sharding.Query, er
 	s.quote(db)
 	s.writeByte('.')
 	s.quote(tbl)
+	if len(s.indexHints) > 0 {
+		if err = s.buildIndexHints(s.shardingIndexHints(tbl)); err != nil {
+			return sharding.EmptyQuery, err
+		}
+	}
 
-	if len(s.where) > 0 {
+	where := s.where
+	if !s.unscoped {
+		// 软删除的条件不参与计算目标表，所以在这里才加上
+		if _, where, err = s.withNotDeleted(nil, where); err != nil {
+			return sharding.EmptyQuery, err
+		}
+	}
+	if len(where) > 0 {
 		s.writeString(" WHERE ")
-		p := s.where[0]
-		for i := 1; i < len(s.where); i++ {
-			p = p.And(s.where[i])
+		p := where[0]
+		for i := 1; i < len(where); i++ {
+			p = p.And(where[i])
 		}
 		if err = s.buildExpr(p); err != nil {
 			return sharding.EmptyQuery, err
@@ -107,14 +127,6 @@ func (s *ShardingSelector[T]) buildQuery(db, tbl, ds string) (sharding.Query, er
 		}
 	}
 
-	// order by
-	if len(s.orderBy) > 0 {
-		err = s.buildOrderBy()
-		if err != nil {
-			return sharding.EmptyQuery, err
-		}
-	}
-
 	// having
 	if len(s.having) > 0 {
 		s.writeString(" HAVING ")
@@ -127,16 +139,21 @@ func (s *ShardingSelector[T]) buildQuery(db, tbl, ds string) (sharding.Query, er
 		}
 	}
 
-	if s.offset > 0 {
-		s.writeString(" OFFSET ")
-		s.parameter(s.offset)
+	// order by
+	if len(s.orderBy) > 0 {
+		err = s.buildOrderBy()
+		if err != nil {
+			return sharding.EmptyQuery, err
+		}
 	}
 
+	s.buildLimitOffset(s.limit, s.offset)
 	if s.limit > 0 {
-		s.writeString(" LIMIT ")
-		s.parameter(s.limit)
 		s.queryFeature |= query.Limit
 	}
+	if err = s.buildLock(s.lock); err != nil {
+		return sharding.EmptyQuery, err
+	}
 	s.end()
 	return sharding.Query{SQL: s.buffer.String(), Args: s.args, Datasource: ds, DB: db}, nil
 }
@@ -236,13 +253,37 @@ func (s *ShardingSelector[T]) buildExpr(expr Expr) error {
 	return nil
 }
 
+// shardingIndexHints 返回物理表 tbl 上的索引提示
+func (s *ShardingSelector[T]) shardingIndexHints(tbl string) []dialect.IndexHint {
+	if s.rewriteIndex == nil {
+		return s.indexHints
+	}
+	hints := make([]dialect.IndexHint, 0, len(s.indexHints))
+	for _, h := range s.indexHints {
+		indexes := make([]string, 0, len(h.Indexes))
+		for _, idx := range h.Indexes {
+			indexes = append(indexes, s.rewriteIndex(idx, tbl))
+		}
+		hints = append(hints, dialect.IndexHint{Type: h.Type, Indexes: indexes})
+	}
+	return hints
+}
+
 func (s *ShardingSelector[T]) buildOrderBy() error {
 	s.writeString(" ORDER BY ")
 	for i, ob := range s.orderBy {
 		if i > 0 {
 			s.comma()
 		}
-		for _, c := range ob.fields {
+		// 归并结果集的时候需要知道排序的列和 NULL 的顺序，
+		// 所以暂不支持按照表达式排序，也不支持 NULLS FIRST/LAST
+		if ob.expr != nil || ob.nulls != "" {
+			return errs.ErrUnsupportedTooComplexQuery
+		}
+		for j, c := range ob.fields {
+			if j > 0 {
+				s.comma()
+			}
 			cMeta, ok := s.meta.FieldMap[c]
 			if !ok {
 				return errs.NewInvalidFieldError(c)
@@ -284,7 +325,7 @@ func (s *ShardingSelector[T]) Get(ctx context.Context) (*T, error) {
 	if len(qs) > 1 {
 		return nil, errs.ErrOnlyResultOneQuery
 	}
-	q := qs[0]
+	q := s.commenter.comment(ctx, qs[0])
 	// TODO 利用 ctx 传递 DB name
 	row, err := s.db.queryContext(ctx, q)
 	if err != nil {
@@ -312,6 +353,9 @@ func (s *ShardingSelector[T]) GetMulti(ctx context.Context) ([]*T, error) {
 	if err != nil {
 		return nil, err
 	}
+	for i, q := range qs {
+		qs[i] = s.commenter.comment(ctx, q)
+	}
 	rowsList, err := s.db.queryMulti(ctx, qs)
 	if err != nil {
 		return nil, err
@@ -346,6 +390,12 @@ func (s *ShardingSelector[T]) From(tbl *T) *ShardingSelector[T] {
 	return s
 }
 
+// Unscoped 不再自动过滤被软删除的数据
+func (s *ShardingSelector[T]) Unscoped() *ShardingSelector[T] {
+	s.unscoped = true
+	return s
+}
+
 // Where accepts predicates
 func (s *ShardingSelector[T]) Where(predicates ...Predicate) *ShardingSelector[T] {
 	s.where = predicates
@@ -381,3 +431,58 @@ func (s *ShardingSelector[T]) Offset(offset int) *ShardingSelector[T] {
 	s.offset = offset
 	return s
 }
+
+// Hints 指定优化器提示，每一个目标表上的查询都会带上 /*+ hint1 hint2 */
+func (s *ShardingSelector[T]) Hints(hints ...string) *ShardingSelector[T] {
+	s.hints = append(s.hints, hints...)
+	return s
+}
+
+// UseIndex 即 USE INDEX，索引名可以通过 RewriteIndex 改写
+func (s *ShardingSelector[T]) UseIndex(indexes ...string) *ShardingSelector[T] {
+	s.indexHints = append(s.indexHints, dialect.IndexHint{Type: dialect.IndexHintUse, Indexes: indexes})
+	return s
+}
+
+// ForceIndex 即 FORCE INDEX，索引名可以通过 RewriteIndex 改写
+func (s *ShardingSelector[T]) ForceIndex(indexes ...string) *ShardingSelector[T] {
+	s.indexHints = append(s.indexHints, dialect.IndexHint{Type: dialect.IndexHintForce, Indexes: indexes})
+	return s
+}
+
+// IgnoreIndex 即 IGNORE INDEX，索引名可以通过 RewriteIndex 改写
+func (s *ShardingSelector[T]) IgnoreIndex(indexes ...string) *ShardingSelector[T] {
+	s.indexHints = append(s.indexHints, dialect.IndexHint{Type: dialect.IndexHintIgnore, Indexes: indexes})
+	return s
+}
+
+// RewriteIndex 指定索引名的改写规则，fn 的参数是逻辑表上的索引名和物理表名，
+// 用于不同的物理表上索引名字不同的场景，例如索引名带上了表名
+func (s *ShardingSelector[T]) RewriteIndex(fn func(index string, table string) string) *ShardingSelector[T] {
+	s.rewriteIndex = fn
+	return s
+}
+
+// ForUpdate 加排他锁，每一个目标表上的查询都会带上 FOR UPDATE
+func (s *ShardingSelector[T]) ForUpdate() *ShardingSelector[T] {
+	s.lock.Strength = dialect.LockForUpdate
+	return s
+}
+
+// ForShare 加共享锁，每一个目标表上的查询都会带上 FOR SHARE
+func (s *ShardingSelector[T]) ForShare() *ShardingSelector[T] {
+	s.lock.Strength = dialect.LockForShare
+	return s
+}
+
+// NoWait 行已经被锁住的时候，不等待而是直接返回错误
+func (s *ShardingSelector[T]) NoWait() *ShardingSelector[T] {
+	s.lock.Wait = dialect.LockNoWait
+	return s
+}
+
+// SkipLocked 跳过已经被锁住的行
+func (s *ShardingSelector[T]) SkipLocked() *ShardingSelector[T] {
+	s.lock.Wait = dialect.LockSkipLocked
+	return s
+}
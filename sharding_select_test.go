@@ -4570,6 +4570,54 @@ func TestShardingSelector_Build(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "between and eq",
+			builder: func() sharding.QueryBuilder {
+				s := NewShardingSelector[Order](shardingDB).
+					Where(C("UserId").Between(100, 200).And(C("UserId").EQ(123)))
+				return s
+			}(),
+			qs: []sharding.Query{
+				{
+					SQL:        "SELECT `user_id`,`order_id`,`content`,`account` FROM `order_db_1`.`order_tab_0` WHERE (`user_id` BETWEEN ? AND ?) AND (`user_id`=?);",
+					Args:       []any{100, 200, 123},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "not not between and eq",
+			builder: func() sharding.QueryBuilder {
+				s := NewShardingSelector[Order](shardingDB).
+					Where(Not(C("UserId").NotBetween(100, 200)).And(C("UserId").EQ(123)))
+				return s
+			}(),
+			qs: []sharding.Query{
+				{
+					SQL:        "SELECT `user_id`,`order_id`,`content`,`account` FROM `order_db_1`.`order_tab_0` WHERE (NOT (`user_id` NOT BETWEEN ? AND ?)) AND (`user_id`=?);",
+					Args:       []any{100, 200, 123},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "is null and eq",
+			builder: func() sharding.QueryBuilder {
+				s := NewShardingSelector[Order](shardingDB).
+					Where(C("Content").IsNull().And(Not(C("Account").NotNull())).And(C("UserId").EQ(123)))
+				return s
+			}(),
+			qs: []sharding.Query{
+				{
+					SQL:        "SELECT `user_id`,`order_id`,`content`,`account` FROM `order_db_1`.`order_tab_0` WHERE ((`content` IS NULL) AND (NOT (`account` IS NOT NULL))) AND (`user_id`=?);",
+					Args:       []any{123},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
 		{
 			name: "only eq broadcast",
 			builder: func() sharding.QueryBuilder {
@@ -5779,6 +5827,176 @@ func TestShardingSelector_Build(t *testing.T) {
 	}
 }
 
+func TestShardingSelector_Lock(t *testing.T) {
+	r := model.NewMetaRegistry()
+	_, err := r.Register(&Order{},
+		model.WithTableShardingAlgorithm(&hash.Hash{
+			ShardingKey:  "UserId",
+			DBPattern:    &hash.Pattern{Name: "order_db_%d", Base: 2},
+			TablePattern: &hash.Pattern{Name: "order_tab_%d", Base: 3},
+			DsPattern:    &hash.Pattern{Name: "0.db.cluster.company.com:3306", NotSharding: true},
+		}))
+	require.NoError(t, err)
+	m := map[string]*masterslave.MasterSlavesDB{
+		"order_db_0": MasterSlavesMemoryDB(),
+		"order_db_1": MasterSlavesMemoryDB(),
+	}
+	ds := map[string]datasource.DataSource{
+		"0.db.cluster.company.com:3306": cluster.NewClusterDB(m),
+	}
+	mysqlDB, err := OpenDS("mysql",
+		shardingsource.NewShardingDataSource(ds), DBWithMetaRegistry(r))
+	require.NoError(t, err)
+	sqliteDB, err := OpenDS("sqlite3",
+		shardingsource.NewShardingDataSource(ds), DBWithMetaRegistry(r))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		builder sharding.QueryBuilder
+		qs      []sharding.Query
+		wantErr error
+	}{
+		{
+			name: "for update",
+			builder: NewShardingSelector[Order](mysqlDB).
+				Where(C("UserId").EQ(123)).ForUpdate().SkipLocked(),
+			qs: []sharding.Query{
+				{
+					SQL:        "SELECT `user_id`,`order_id`,`content`,`account` FROM `order_db_1`.`order_tab_0` WHERE `user_id`=? FOR UPDATE SKIP LOCKED;",
+					Args:       []any{123},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "broadcast for share",
+			builder: NewShardingSelector[Order](mysqlDB).
+				Where(C("UserId").In(1, 2)).ForShare(),
+			qs: []sharding.Query{
+				{
+					SQL:        "SELECT `user_id`,`order_id`,`content`,`account` FROM `order_db_0`.`order_tab_2` WHERE `user_id` IN (?,?) FOR SHARE;",
+					Args:       []any{1, 2},
+					DB:         "order_db_0",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+				{
+					SQL:        "SELECT `user_id`,`order_id`,`content`,`account` FROM `order_db_1`.`order_tab_1` WHERE `user_id` IN (?,?) FOR SHARE;",
+					Args:       []any{1, 2},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "sqlite",
+			builder: NewShardingSelector[Order](sqliteDB).
+				Where(C("UserId").EQ(123)).ForUpdate(),
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "FOR UPDATE"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qs, err := tc.builder.Build(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.ElementsMatch(t, tc.qs, qs)
+		})
+	}
+}
+
+func TestShardingSelector_Hints(t *testing.T) {
+	r := model.NewMetaRegistry()
+	_, err := r.Register(&Order{},
+		model.WithTableShardingAlgorithm(&hash.Hash{
+			ShardingKey:  "UserId",
+			DBPattern:    &hash.Pattern{Name: "order_db_%d", Base: 2},
+			TablePattern: &hash.Pattern{Name: "order_tab_%d", Base: 3},
+			DsPattern:    &hash.Pattern{Name: "0.db.cluster.company.com:3306", NotSharding: true},
+		}))
+	require.NoError(t, err)
+	m := map[string]*masterslave.MasterSlavesDB{
+		"order_db_0": MasterSlavesMemoryDB(),
+		"order_db_1": MasterSlavesMemoryDB(),
+	}
+	ds := map[string]datasource.DataSource{
+		"0.db.cluster.company.com:3306": cluster.NewClusterDB(m),
+	}
+	mysqlDB, err := OpenDS("mysql",
+		shardingsource.NewShardingDataSource(ds), DBWithMetaRegistry(r))
+	require.NoError(t, err)
+	sqliteDB, err := OpenDS("sqlite3",
+		shardingsource.NewShardingDataSource(ds), DBWithMetaRegistry(r))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		builder sharding.QueryBuilder
+		qs      []sharding.Query
+		wantErr error
+	}{
+		{
+			name: "optimizer hints and force index",
+			builder: NewShardingSelector[Order](mysqlDB).Hints("MAX_EXECUTION_TIME(1000)").
+				ForceIndex("idx_user_id").Where(C("UserId").EQ(123)),
+			qs: []sharding.Query{
+				{
+					SQL:        "SELECT /*+ MAX_EXECUTION_TIME(1000) */ `user_id`,`order_id`,`content`,`account` FROM `order_db_1`.`order_tab_0` FORCE INDEX (`idx_user_id`) WHERE `user_id`=?;",
+					Args:       []any{123},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "rewrite index",
+			builder: NewShardingSelector[Order](mysqlDB).UseIndex("idx_user_id").
+				RewriteIndex(func(index string, table string) string {
+					return table + "_" + index
+				}).Where(C("UserId").In(1, 2)),
+			qs: []sharding.Query{
+				{
+					SQL:        "SELECT `user_id`,`order_id`,`content`,`account` FROM `order_db_0`.`order_tab_2` USE INDEX (`order_tab_2_idx_user_id`) WHERE `user_id` IN (?,?);",
+					Args:       []any{1, 2},
+					DB:         "order_db_0",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+				{
+					SQL:        "SELECT `user_id`,`order_id`,`content`,`account` FROM `order_db_1`.`order_tab_1` USE INDEX (`order_tab_1_idx_user_id`) WHERE `user_id` IN (?,?);",
+					Args:       []any{1, 2},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "hint closing comment",
+			builder: NewShardingSelector[Order](mysqlDB).Hints("NO_ICP(order_tab_0) */").
+				Where(C("UserId").EQ(123)),
+			wantErr: errs.NewInvalidHintError("NO_ICP(order_tab_0) */"),
+		},
+		{
+			name: "sqlite",
+			builder: NewShardingSelector[Order](sqliteDB).
+				Where(C("UserId").EQ(123)).IgnoreIndex("idx_user_id"),
+			wantErr: errs.NewUnsupportedClauseError("SQLite", "IGNORE INDEX"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qs, err := tc.builder.Build(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.ElementsMatch(t, tc.qs, qs)
+		})
+	}
+}
+
 func TestShardingSelector_Build_Error(t *testing.T) {
 	r := model.NewMetaRegistry()
 	dbBase, tableBase := 2, 3
@@ -5811,6 +6029,32 @@ func TestShardingSelector_Build_Error(t *testing.T) {
 		qs      []sharding.Query
 		wantErr error
 	}{
+		{
+			name: "between with column",
+			builder: func() sharding.QueryBuilder {
+				s := NewShardingSelector[Order](shardingDB).Where(C("UserId").Between(C("OrderId"), 10))
+				return s
+			}(),
+			wantErr: errs.ErrUnsupportedTooComplexQuery,
+		},
+		{
+			name: "order by expr",
+			builder: func() sharding.QueryBuilder {
+				s := NewShardingSelector[Order](shardingDB).Where(C("UserId").EQ(123)).
+					OrderBy(Lower(C("Content")).ASC())
+				return s
+			}(),
+			wantErr: errs.ErrUnsupportedTooComplexQuery,
+		},
+		{
+			name: "order by nulls first",
+			builder: func() sharding.QueryBuilder {
+				s := NewShardingSelector[Order](shardingDB).Where(C("UserId").EQ(123)).
+					OrderBy(ASC("Content").NullsFirst())
+				return s
+			}(),
+			wantErr: errs.ErrUnsupportedTooComplexQuery,
+		},
 		{
 			name: "not and left too complex operator",
 			builder: func() sharding.QueryBuilder {
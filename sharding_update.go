@@ -56,6 +56,12 @@ func (s *ShardingUpdater[T]) Set(assigns ...Assignable) *ShardingUpdater[T] {
 	return s
 }
 
+// Unscoped 被软删除的数据也会被更新
+func (s *ShardingUpdater[T]) Unscoped() *ShardingUpdater[T] {
+	s.unscoped = true
+	return s
+}
+
 func (s *ShardingUpdater[T]) Where(predicates ...Predicate) *ShardingUpdater[T] {
 	s.where = predicates
 	return s
@@ -111,9 +117,16 @@ func (s *ShardingUpdater[T]) buildQuery(db, tbl, ds string) (sharding.Query, err
 		return sharding.EmptyQuery, err
 	}
 
-	if len(s.where) > 0 {
+	where := s.where
+	if !s.unscoped {
+		// 软删除的条件不参与计算目标表，所以在这里才加上
+		if _, where, err = s.withNotDeleted(nil, where); err != nil {
+			return sharding.EmptyQuery, err
+		}
+	}
+	if len(where) > 0 {
 		s.writeString(" WHERE ")
-		err = s.buildPredicates(s.where)
+		err = s.buildPredicates(where)
 		if err != nil {
 			return sharding.EmptyQuery, err
 		}
@@ -125,6 +138,7 @@ func (s *ShardingUpdater[T]) buildQuery(db, tbl, ds string) (sharding.Query, err
 
 func (s *ShardingUpdater[T]) buildAssigns() error {
 	has := false
+	now := s.now()
 	shardingKey := s.meta.ShardingAlgorithm.ShardingKeys()[0]
 	for _, assign := range s.assigns {
 		if has {
@@ -139,13 +153,9 @@ func (s *ShardingUpdater[T]) buildAssigns() error {
 			if !ok {
 				return errs.NewInvalidFieldError(a.name)
 			}
-			refVal, err := s.val.Field(a.name)
-			if err != nil {
-				return err
-			}
 			s.quote(c.ColumnName)
 			_ = s.buffer.WriteByte('=')
-			s.parameter(refVal.Interface())
+			s.parameter(s.assignValue(c, now))
 			has = true
 		case columns:
 			for _, name := range a.cs {
@@ -156,16 +166,12 @@ func (s *ShardingUpdater[T]) buildAssigns() error {
 				if !ok {
 					return errs.NewInvalidFieldError(name)
 				}
-				refVal, err := s.val.Field(name)
-				if err != nil {
-					return err
-				}
 				if has {
 					s.comma()
 				}
 				s.quote(c.ColumnName)
 				_ = s.buffer.WriteByte('=')
-				s.parameter(refVal.Interface())
+				s.parameter(s.assignValue(c, now))
 				has = true
 			}
 		case Assignment:
@@ -180,22 +186,29 @@ func (s *ShardingUpdater[T]) buildAssigns() error {
 	if !has {
 		return errs.NewValueNotSetError()
 	}
+	for _, c := range s.autoUpdateColumns(s.meta) {
+		if c.FieldName == shardingKey {
+			continue
+		}
+		s.comma()
+		s.quote(c.ColumnName)
+		_ = s.buffer.WriteByte('=')
+		s.parameter(c.TimeValue(now))
+	}
 	return nil
 }
 
 func (s *ShardingUpdater[T]) buildDefaultColumns() error {
 	has := false
+	now := s.now()
 	shardingKey := s.meta.ShardingAlgorithm.ShardingKeys()[0]
 	for _, c := range s.meta.Columns {
 		fieldName := c.FieldName
 		if fieldName == shardingKey {
 			continue
 		}
-		refVal, _ := s.val.Field(fieldName)
-		if s.ignoreZeroVal && isZeroValue(refVal) {
-			continue
-		}
-		if s.ignoreNilVal && isNilValue(refVal) {
+		val, ok := s.defaultColumnValue(c, now)
+		if !ok {
 			continue
 		}
 		if has {
@@ -203,7 +216,7 @@ func (s *ShardingUpdater[T]) buildDefaultColumns() error {
 		}
 		s.quote(c.ColumnName)
 		_ = s.buffer.WriteByte('=')
-		s.parameter(refVal.Interface())
+		s.parameter(val)
 		has = true
 	}
 	if !has {
@@ -234,7 +247,7 @@ func (s *ShardingUpdater[T]) Exec(ctx context.Context) sharding.Result {
 	for idx, q := range qs {
 		go func(idx int, q Query) {
 			defer wg.Done()
-			res, err := s.db.execContext(ctx, q)
+			res, err := s.db.execContext(ctx, s.commenter.comment(ctx, q))
 			s.lock.Lock()
 			errList[idx] = err
 			resList[idx] = res
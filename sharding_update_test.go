@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"go.uber.org/multierr"
 
@@ -866,6 +867,68 @@ func (s *ShardingUpdaterSuite) TestShardingUpdater_Exec() {
 	}
 }
 
+func TestShardingUpdater_AutoTime(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	r := model.NewMetaRegistry()
+	_, err := r.Register(&autoTimeModel{},
+		model.WithTableShardingAlgorithm(&hash.Hash{
+			ShardingKey:  "Id",
+			DBPattern:    &hash.Pattern{Name: "order_db_%d", Base: 2},
+			TablePattern: &hash.Pattern{Name: "order_tab_%d", Base: 3},
+			DsPattern:    &hash.Pattern{Name: "0.db.cluster.company.com:3306", NotSharding: true},
+		}))
+	require.NoError(t, err)
+	clusterDB := cluster.NewClusterDB(map[string]*masterslave.MasterSlavesDB{
+		"order_db_1": MasterSlavesMemoryDB(),
+	})
+	ds := map[string]datasource.DataSource{
+		"0.db.cluster.company.com:3306": clusterDB,
+	}
+	shardingDB, err := OpenDS("sqlite3", shardingsource.NewShardingDataSource(ds),
+		DBWithMetaRegistry(r), DBWithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		builder sharding.QueryBuilder
+		wantQs  []sharding.Query
+	}{
+		{
+			name: "skip zero value",
+			builder: NewShardingUpdater[autoTimeModel](shardingDB).Update(&autoTimeModel{Name: "Tom"}).
+				SkipZeroValue().Where(C("Id").EQ(1)),
+			wantQs: []sharding.Query{
+				{
+					SQL:        "UPDATE `order_db_1`.`order_tab_1` SET `name`=?,`update_time`=? WHERE `id`=?;",
+					Args:       []any{"Tom", int64(1700000000123), 1},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+		{
+			name: "set",
+			builder: NewShardingUpdater[autoTimeModel](shardingDB).Update(&autoTimeModel{Name: "Tom"}).
+				Set(C("Name")).Where(C("Id").EQ(1)),
+			wantQs: []sharding.Query{
+				{
+					SQL:        "UPDATE `order_db_1`.`order_tab_1` SET `name`=?,`update_time`=? WHERE `id`=?;",
+					Args:       []any{"Tom", int64(1700000000123), 1},
+					DB:         "order_db_1",
+					Datasource: "0.db.cluster.company.com:3306",
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qs, err := tc.builder.Build(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantQs, qs)
+		})
+	}
+}
+
 func TestShardingUpdaterSuite(t *testing.T) {
 	suite.Run(t, &ShardingUpdaterSuite{})
 }
@@ -0,0 +1,130 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"github.com/ecodeclub/eorm/internal/dialect"
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/ecodeclub/eorm/internal/model"
+)
+
+// withNotDeleted 在 predicates 的基础上加上没有被软删除的条件，
+// 例如 `deleted_at` IS NULL 或者 `is_deleted`=false。
+// 对于 JOIN，会给其中每一个支持软删除的表加上条件：
+// 会保留所有行的一边放在 WHERE 里面，另外一边放在 ON 里面，
+// 这样 LEFT JOIN 和 RIGHT JOIN 的语义不会发生变化，所以同时返回改写之后的 table。
+// 子查询和 CTE 需要用户自己处理
+func (b *builder) withNotDeleted(table TableReference, predicates []Predicate) (TableReference, []Predicate, error) {
+	var (
+		conds []Predicate
+		err   error
+	)
+	switch tbl := table.(type) {
+	case nil:
+		conds = notDeletedOf(nil, b.meta)
+	case Table:
+		conds, err = b.tableNotDeleted(tbl)
+	case Join:
+		table, conds, err = b.joinNotDeleted(tbl)
+	}
+	if err != nil || len(conds) == 0 {
+		return table, predicates, err
+	}
+	// 不能修改 predicates，因为 Build 可能会被调用多次
+	res := make([]Predicate, 0, len(predicates)+len(conds))
+	res = append(res, predicates...)
+	return table, append(res, conds...), nil
+}
+
+// tableNotDeleted 返回 tbl 没有被软删除的条件
+func (b *builder) tableNotDeleted(tbl Table) ([]Predicate, error) {
+	if tbl.cte {
+		return nil, nil
+	}
+	meta, err := b.metaRegistry.Get(tbl.entity)
+	if err != nil {
+		return nil, err
+	}
+	return notDeletedOf(tbl, meta), nil
+}
+
+// joinNotDeleted 返回改写之后的 JOIN，以及需要放到 WHERE 里面的条件。
+// 被外连接补 NULL 的一边，条件放在 ON 里面，否则这些行会被 WHERE 过滤掉
+func (b *builder) joinNotDeleted(j Join) (Join, []Predicate, error) {
+	left, leftConds, err := b.sideNotDeleted(j.left)
+	if err != nil {
+		return j, nil, err
+	}
+	right, rightConds, err := b.sideNotDeleted(j.right)
+	if err != nil {
+		return j, nil, err
+	}
+	j.left, j.right = left, right
+	var where, on []Predicate
+	switch j.typ {
+	case "LEFT JOIN":
+		where, on = leftConds, rightConds
+	case "RIGHT JOIN":
+		where, on = rightConds, leftConds
+	case dialect.JoinFullOuter:
+		// 条件不管是放在 ON 还是 WHERE 里面都不对：
+		// 放在 ON 里面依旧会返回已经删除的行，放在 WHERE 里面又退化成了内连接
+		if len(leftConds) > 0 || len(rightConds) > 0 {
+			return j, nil, errs.ErrSoftDeleteFullOuterJoin
+		}
+	default:
+		where = append(leftConds, rightConds...)
+	}
+	if len(on) > 0 {
+		// USING 和 ON 不能同时使用
+		if len(j.using) > 0 {
+			return j, nil, errs.ErrSoftDeleteOuterJoinUsing
+		}
+		joinOn := make([]Predicate, 0, len(j.on)+len(on))
+		joinOn = append(joinOn, j.on...)
+		j.on = append(joinOn, on...)
+	}
+	return j, where, nil
+}
+
+// sideNotDeleted 处理 JOIN 的其中一边
+func (b *builder) sideNotDeleted(table TableReference) (TableReference, []Predicate, error) {
+	switch tbl := table.(type) {
+	case Table:
+		conds, err := b.tableNotDeleted(tbl)
+		return tbl, conds, err
+	case Join:
+		return b.joinNotDeleted(tbl)
+	default:
+		return table, nil, nil
+	}
+}
+
+// notDeletedOf table 为 nil 的时候，列不带表名
+func notDeletedOf(table TableReference, meta *model.TableMeta) []Predicate {
+	cm := meta.SoftDeleteColumn
+	if cm == nil {
+		return nil
+	}
+	return []Predicate{notDeleted(Column{table: table, name: cm.FieldName}, cm)}
+}
+
+func notDeleted(c Column, cm *model.ColumnMeta) Predicate {
+	val := cm.NotDeletedValue()
+	if val == nil {
+		return c.IsNull()
+	}
+	return c.EQ(val)
+}
@@ -0,0 +1,398 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/eorm/internal/datasource"
+	"github.com/ecodeclub/eorm/internal/datasource/cluster"
+	"github.com/ecodeclub/eorm/internal/datasource/masterslave"
+	"github.com/ecodeclub/eorm/internal/datasource/shardingsource"
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/ecodeclub/eorm/internal/model"
+	"github.com/ecodeclub/eorm/internal/sharding"
+	"github.com/ecodeclub/eorm/internal/sharding/hash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type softDeleteModel struct {
+	Id        int `eorm:"primary_key"`
+	Name      string
+	DeletedAt *time.Time `eorm:"softDelete"`
+}
+
+type flagDeleteModel struct {
+	Id        int  `eorm:"primary_key"`
+	IsDeleted bool `eorm:"softDelete"`
+}
+
+func TestSoftDelete(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	clock := DBWithClock(func() time.Time { return now })
+	db, err := Open("sqlite3", "file:test.db?cache=shared&mode=memory", clock)
+	require.NoError(t, err)
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test", clock)
+	require.NoError(t, err)
+	t1 := TableOf(&softDeleteModel{}, "t1")
+	testCases := []CommonTestCase{
+		{
+			name:    "select",
+			builder: NewSelector[softDeleteModel](db),
+			wantSql: "SELECT `id`,`name`,`deleted_at` FROM `soft_delete_model` WHERE `deleted_at` IS NULL;",
+		},
+		{
+			name:     "select with where",
+			builder:  NewSelector[softDeleteModel](db).Select(C("Name")).Where(C("Id").EQ(1)),
+			wantSql:  "SELECT `name` FROM `soft_delete_model` WHERE (`id`=?) AND (`deleted_at` IS NULL);",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "select unscoped",
+			builder:  NewSelector[softDeleteModel](db).Select(C("Name")).Where(C("Id").EQ(1)).Unscoped(),
+			wantSql:  "SELECT `name` FROM `soft_delete_model` WHERE `id`=?;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "select from table",
+			builder:  NewSelector[softDeleteModel](db).Select(t1.C("Name")).From(t1).Where(t1.C("Id").EQ(1)),
+			wantSql:  "SELECT `t1`.`name` FROM `soft_delete_model` AS `t1` WHERE (`t1`.`id`=?) AND (`t1`.`deleted_at` IS NULL);",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "select flag",
+			builder:  NewSelector[flagDeleteModel](db).Select(C("Id")),
+			wantSql:  "SELECT `id` FROM `flag_delete_model` WHERE `is_deleted`=?;",
+			wantArgs: []interface{}{false},
+		},
+		{
+			name: "select join",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Id"))))
+			}(),
+			wantSql:  "SELECT `t1`.`name` FROM (`soft_delete_model` AS `t1` JOIN `flag_delete_model` AS `t2` ON `t1`.`id`=`t2`.`id`) WHERE (`t1`.`deleted_at` IS NULL) AND (`t2`.`is_deleted`=?);",
+			wantArgs: []interface{}{false},
+		},
+		{
+			name: "select join unscoped",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).
+					From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Id")))).Unscoped()
+			}(),
+			wantSql: "SELECT `t1`.`name` FROM (`soft_delete_model` AS `t1` JOIN `flag_delete_model` AS `t2` ON `t1`.`id`=`t2`.`id`);",
+		},
+		{
+			name: "select left join",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).
+					From(t1.LeftJoin(t2).On(t1.C("Id").EQ(t2.C("Id")))).Where(t1.C("Id").EQ(1))
+			}(),
+			wantSql:  "SELECT `t1`.`name` FROM (`soft_delete_model` AS `t1` LEFT JOIN `flag_delete_model` AS `t2` ON (`t1`.`id`=`t2`.`id`) AND (`t2`.`is_deleted`=?)) WHERE (`t1`.`id`=?) AND (`t1`.`deleted_at` IS NULL);",
+			wantArgs: []interface{}{false, 1},
+		},
+		{
+			name: "select right join",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).
+					From(t1.RightJoin(t2).On(t1.C("Id").EQ(t2.C("Id"))))
+			}(),
+			wantSql:  "SELECT `t1`.`name` FROM (`soft_delete_model` AS `t1` RIGHT JOIN `flag_delete_model` AS `t2` ON (`t1`.`id`=`t2`.`id`) AND (`t1`.`deleted_at` IS NULL)) WHERE `t2`.`is_deleted`=?;",
+			wantArgs: []interface{}{false},
+		},
+		{
+			name: "select full outer join",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).
+					From(t1.FullOuterJoin(t2).On(t1.C("Id").EQ(t2.C("Id"))))
+			}(),
+			wantErr: errs.ErrSoftDeleteFullOuterJoin,
+		},
+		{
+			name: "select full outer join unscoped",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).
+					From(t1.FullOuterJoin(t2).On(t1.C("Id").EQ(t2.C("Id")))).Unscoped()
+			}(),
+			wantSql: "SELECT `t1`.`name` FROM (`soft_delete_model` AS `t1` FULL OUTER JOIN `flag_delete_model` AS `t2` ON `t1`.`id`=`t2`.`id`);",
+		},
+		{
+			name: "select nested join",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				t3 := TableOf(&TestModel{}, "t3")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).
+					From(t1.LeftJoin(t2.Join(t3).On(t2.C("Id").EQ(t3.C("Id")))).On(t1.C("Id").EQ(t2.C("Id"))))
+			}(),
+			wantSql:  "SELECT `t1`.`name` FROM (`soft_delete_model` AS `t1` LEFT JOIN (`flag_delete_model` AS `t2` JOIN `test_model` AS `t3` ON `t2`.`id`=`t3`.`id`) ON (`t1`.`id`=`t2`.`id`) AND (`t2`.`is_deleted`=?)) WHERE `t1`.`deleted_at` IS NULL;",
+			wantArgs: []interface{}{false},
+		},
+		{
+			name: "select left join using",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).From(t1.LeftJoin(t2).Using("Id"))
+			}(),
+			wantErr: errs.ErrSoftDeleteOuterJoinUsing,
+		},
+		{
+			name: "select inner join using",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewSelector[softDeleteModel](db).Select(t1.C("Name")).From(t1.Join(t2).Using("Id"))
+			}(),
+			wantSql:  "SELECT `t1`.`name` FROM (`soft_delete_model` AS `t1` JOIN `flag_delete_model` AS `t2` USING (`id`)) WHERE (`t1`.`deleted_at` IS NULL) AND (`t2`.`is_deleted`=?);",
+			wantArgs: []interface{}{false},
+		},
+		{
+			name:     "update",
+			builder:  NewUpdater[softDeleteModel](db).Update(&softDeleteModel{Name: "Tom"}).Set(C("Name")).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `soft_delete_model` SET `name`=? WHERE (`id`=?) AND (`deleted_at` IS NULL);",
+			wantArgs: []interface{}{"Tom", 1},
+		},
+		{
+			name:     "update unscoped",
+			builder:  NewUpdater[softDeleteModel](db).Update(&softDeleteModel{Name: "Tom"}).Set(C("Name")).Unscoped(),
+			wantSql:  "UPDATE `soft_delete_model` SET `name`=?;",
+			wantArgs: []interface{}{"Tom"},
+		},
+		{
+			name: "update left join",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewUpdater[softDeleteModel](mysqlDB).Table(t1.LeftJoin(t2).On(t1.C("Id").EQ(t2.C("Id")))).
+					Set(t1.C("Name").Assign("Tom"))
+			}(),
+			wantSql:  "UPDATE (`soft_delete_model` AS `t1` LEFT JOIN `flag_delete_model` AS `t2` ON (`t1`.`id`=`t2`.`id`) AND (`t2`.`is_deleted`=?)) SET `t1`.`name`=? WHERE `t1`.`deleted_at` IS NULL;",
+			wantArgs: []interface{}{false, "Tom"},
+		},
+		{
+			name:     "delete",
+			builder:  NewDeleter[softDeleteModel](db).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `soft_delete_model` SET `deleted_at`=? WHERE (`id`=?) AND (`deleted_at` IS NULL);",
+			wantArgs: []interface{}{&now, 1},
+		},
+		{
+			name:     "delete unscoped",
+			builder:  NewDeleter[softDeleteModel](db).Where(C("Id").EQ(1)).Unscoped(),
+			wantSql:  "UPDATE `soft_delete_model` SET `deleted_at`=? WHERE `id`=?;",
+			wantArgs: []interface{}{&now, 1},
+		},
+		{
+			name:     "hard delete",
+			builder:  NewDeleter[softDeleteModel](db).Where(C("Id").EQ(1)).HardDelete(),
+			wantSql:  "DELETE FROM `soft_delete_model` WHERE `id`=?;",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "delete flag",
+			builder:  NewDeleter[flagDeleteModel](db),
+			wantSql:  "UPDATE `flag_delete_model` SET `is_deleted`=? WHERE `is_deleted`=?;",
+			wantArgs: []interface{}{true, false},
+		},
+		{
+			name:     "delete from table",
+			builder:  NewDeleter[softDeleteModel](db).From(t1).Where(t1.C("Id").EQ(1)),
+			wantSql:  "UPDATE `soft_delete_model` AS `t1` SET `deleted_at`=? WHERE (`t1`.`id`=?) AND (`t1`.`deleted_at` IS NULL);",
+			wantArgs: []interface{}{&now, 1},
+		},
+		{
+			name:     "delete with order by and limit",
+			builder:  NewDeleter[softDeleteModel](mysqlDB).OrderBy(ASC("Id")).Limit(10),
+			wantSql:  "UPDATE `soft_delete_model` SET `deleted_at`=? WHERE `deleted_at` IS NULL ORDER BY `id` ASC LIMIT ?;",
+			wantArgs: []interface{}{&now, 10},
+		},
+		{
+			name: "delete join",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewDeleter[softDeleteModel](mysqlDB).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Id"))))
+			}(),
+			wantErr: errs.ErrSoftDeleteJoin,
+		},
+		{
+			name: "hard delete join",
+			builder: func() QueryBuilder {
+				t2 := TableOf(&flagDeleteModel{}, "t2")
+				return NewDeleter[softDeleteModel](mysqlDB).From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Id")))).HardDelete()
+			}(),
+			wantSql: "DELETE `t1` FROM (`soft_delete_model` AS `t1` JOIN `flag_delete_model` AS `t2` ON `t1`.`id`=`t2`.`id`);",
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}
+
+func TestSoftDelete_Query(t *testing.T) {
+	db := memoryDBWithDB("soft_delete")
+	defer func() {
+		_ = db.Close()
+	}()
+	ctx := context.Background()
+	for _, ddl := range []string{
+		"CREATE TABLE `soft_delete_model`(`id` INTEGER PRIMARY KEY, `name` TEXT, `deleted_at` DATETIME);",
+		"CREATE TABLE `flag_delete_model`(`id` INTEGER PRIMARY KEY, `is_deleted` BOOLEAN);",
+	} {
+		require.NoError(t, RawQuery[any](db, ddl).Exec(ctx).Err())
+	}
+	deletedAt := time.UnixMilli(1700000000123)
+	require.NoError(t, NewInserter[softDeleteModel](db).Values(
+		&softDeleteModel{Id: 1, Name: "Tom"},
+		&softDeleteModel{Id: 2, Name: "Jerry", DeletedAt: &deletedAt},
+		&softDeleteModel{Id: 3, Name: "Bob"},
+	).Exec(ctx).Err())
+	require.NoError(t, NewInserter[flagDeleteModel](db).Values(
+		&flagDeleteModel{Id: 1},
+		&flagDeleteModel{Id: 2},
+		&flagDeleteModel{Id: 3, IsDeleted: true},
+	).Exec(ctx).Err())
+
+	t1 := TableOf(&softDeleteModel{}, "t1")
+	t2 := TableOf(&flagDeleteModel{}, "t2")
+	testCases := []struct {
+		name    string
+		s       *Selector[softDeleteModel]
+		wantIds []int
+		wantErr error
+	}{
+		{
+			name:    "select",
+			s:       NewSelector[softDeleteModel](db).OrderBy(ASC("Id")),
+			wantIds: []int{1, 3},
+		},
+		{
+			name:    "select unscoped",
+			s:       NewSelector[softDeleteModel](db).OrderBy(ASC("Id")).Unscoped(),
+			wantIds: []int{1, 2, 3},
+		},
+		{
+			name: "join",
+			s: NewSelector[softDeleteModel](db).Select(t1.C("Id")).
+				From(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Id")))).OrderBy(OrderByExpr(t1.C("Id"))),
+			wantIds: []int{1},
+		},
+		{
+			// 右边已经删除的行不会影响左边的行
+			name: "left join",
+			s: NewSelector[softDeleteModel](db).Select(t1.C("Id")).
+				From(t1.LeftJoin(t2).On(t1.C("Id").EQ(t2.C("Id")))).OrderBy(OrderByExpr(t1.C("Id"))),
+			wantIds: []int{1, 3},
+		},
+		{
+			name: "full outer join",
+			s: NewSelector[softDeleteModel](db).Select(t1.C("Id")).
+				From(t1.FullOuterJoin(t2).On(t1.C("Id").EQ(t2.C("Id")))),
+			wantErr: errs.ErrSoftDeleteFullOuterJoin,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.s.GetMulti(ctx)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			ids := make([]int, 0, len(res))
+			for _, r := range res {
+				ids = append(ids, r.Id)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+		})
+	}
+
+	// 软删除之后就查询不到了
+	require.NoError(t, NewDeleter[softDeleteModel](db).Where(C("Id").EQ(1)).Exec(ctx).Err())
+	res, err := NewSelector[softDeleteModel](db).GetMulti(ctx)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, 3, res[0].Id)
+}
+
+func TestSoftDelete_Sharding(t *testing.T) {
+	r := model.NewMetaRegistry()
+	_, err := r.Register(&softDeleteModel{},
+		model.WithTableShardingAlgorithm(&hash.Hash{
+			ShardingKey:  "Id",
+			DBPattern:    &hash.Pattern{Name: "order_db_%d", Base: 2},
+			TablePattern: &hash.Pattern{Name: "order_tab_%d", Base: 3},
+			DsPattern:    &hash.Pattern{Name: "0.db.cluster.company.com:3306", NotSharding: true},
+		}))
+	require.NoError(t, err)
+	clusterDB := cluster.NewClusterDB(map[string]*masterslave.MasterSlavesDB{
+		"order_db_1": MasterSlavesMemoryDB(),
+	})
+	ds := map[string]datasource.DataSource{
+		"0.db.cluster.company.com:3306": clusterDB,
+	}
+	shardingDB, err := OpenDS("sqlite3", shardingsource.NewShardingDataSource(ds), DBWithMetaRegistry(r))
+	require.NoError(t, err)
+	testCases := []struct {
+		name    string
+		builder sharding.QueryBuilder
+		wantSQL string
+	}{
+		{
+			name:    "select",
+			builder: NewShardingSelector[softDeleteModel](shardingDB).Select(C("Name")).Where(C("Id").EQ(1)),
+			wantSQL: "SELECT `name` FROM `order_db_1`.`order_tab_1` WHERE (`id`=?) AND (`deleted_at` IS NULL);",
+		},
+		{
+			name:    "select unscoped",
+			builder: NewShardingSelector[softDeleteModel](shardingDB).Select(C("Name")).Where(C("Id").EQ(1)).Unscoped(),
+			wantSQL: "SELECT `name` FROM `order_db_1`.`order_tab_1` WHERE `id`=?;",
+		},
+		{
+			name: "update",
+			builder: NewShardingUpdater[softDeleteModel](shardingDB).Update(&softDeleteModel{Name: "Tom"}).
+				Set(C("Name")).Where(C("Id").EQ(1)),
+			wantSQL: "UPDATE `order_db_1`.`order_tab_1` SET `name`=? WHERE (`id`=?) AND (`deleted_at` IS NULL);",
+		},
+		{
+			name: "update unscoped",
+			builder: NewShardingUpdater[softDeleteModel](shardingDB).Update(&softDeleteModel{Name: "Tom"}).
+				Set(C("Name")).Where(C("Id").EQ(1)).Unscoped(),
+			wantSQL: "UPDATE `order_db_1`.`order_tab_1` SET `name`=? WHERE `id`=?;",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qs, err := tc.builder.Build(context.Background())
+			require.NoError(t, err)
+			require.Len(t, qs, 1)
+			assert.Equal(t, tc.wantSQL, qs[0].SQL)
+		})
+	}
+}
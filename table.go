@@ -14,6 +14,8 @@
 
 package eorm
 
+import "github.com/ecodeclub/eorm/internal/dialect"
+
 type TableReference interface {
 	getAlias() string
 }
@@ -22,6 +24,8 @@ type TableReference interface {
 type Table struct {
 	entity any
 	alias  string
+	// cte 为 true 的时候，代表引用的是 WITH 中定义的 CTE，alias 就是 CTE 的名字
+	cte bool
 }
 
 // TableOf 创建一个 Table 代表一个表
@@ -34,6 +38,17 @@ func TableOf(entity any, alias string) Table {
 	}
 }
 
+// CTEOf 创建一个对 CTE 的引用，entity 用于解析列，name 是 CTE 的名字
+// 一般用于递归 CTE 在定义中引用自身，
+// 例如 WITH RECURSIVE tree AS (... JOIN tree ON ...)
+func CTEOf(entity any, name string) Table {
+	return Table{
+		entity: entity,
+		alias:  name,
+		cte:    true,
+	}
+}
+
 func (t Table) getAlias() string {
 	return t.alias
 }
@@ -63,6 +78,42 @@ func (t Table) RightJoin(right TableReference) *JoinBuilder {
 	}
 }
 
+// FullOuterJoin 即 FULL OUTER JOIN，MySQL 不支持
+func (t Table) FullOuterJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  t,
+		right: right,
+		typ:   dialect.JoinFullOuter,
+	}
+}
+
+// StraightJoin 即 STRAIGHT_JOIN，只有 MySQL 支持
+func (t Table) StraightJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  t,
+		right: right,
+		typ:   dialect.JoinStraight,
+	}
+}
+
+// CrossJoin 即 CROSS JOIN，不需要 ON 或者 USING
+func (t Table) CrossJoin(right TableReference) Join {
+	return Join{
+		left:  t,
+		right: right,
+		typ:   "CROSS JOIN",
+	}
+}
+
+// NaturalJoin 即 NATURAL JOIN，使用两边所有同名的列作为连接条件
+func (t Table) NaturalJoin(right TableReference) Join {
+	return Join{
+		left:  t,
+		right: right,
+		typ:   "NATURAL JOIN",
+	}
+}
+
 func (t Table) C(name string) Column {
 	return Column{
 		name:  name,
@@ -115,8 +166,9 @@ func (t Table) Sum(c string) Aggregate {
 	}
 }
 
+// AllColumns 代表该表的所有列，即 t.*，别名会按照方言转义
 func (t Table) AllColumns() RawExpr {
-	return Raw("`" + t.alias + "`.*")
+	return RawExpr{allOf: t}
 }
 
 type Join struct {
@@ -155,6 +207,42 @@ func (j Join) RightJoin(reference TableReference) *JoinBuilder {
 	}
 }
 
+// FullOuterJoin 即 FULL OUTER JOIN，MySQL 不支持
+func (j Join) FullOuterJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  j,
+		right: right,
+		typ:   dialect.JoinFullOuter,
+	}
+}
+
+// StraightJoin 即 STRAIGHT_JOIN，只有 MySQL 支持
+func (j Join) StraightJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  j,
+		right: right,
+		typ:   dialect.JoinStraight,
+	}
+}
+
+// CrossJoin 即 CROSS JOIN，不需要 ON 或者 USING
+func (j Join) CrossJoin(right TableReference) Join {
+	return Join{
+		left:  j,
+		right: right,
+		typ:   "CROSS JOIN",
+	}
+}
+
+// NaturalJoin 即 NATURAL JOIN，使用两边所有同名的列作为连接条件
+func (j Join) NaturalJoin(right TableReference) Join {
+	return Join{
+		left:  j,
+		right: right,
+		typ:   "NATURAL JOIN",
+	}
+}
+
 type JoinBuilder struct {
 	left  TableReference
 	right TableReference
@@ -196,6 +284,19 @@ func (Subquery) expr() (string, error) {
 	panic("implement me")
 }
 
+// ASC 用于 ORDER BY，按照标量子查询的结果排序
+func (s Subquery) ASC() OrderBy {
+	return OrderBy{expr: s, order: "ASC"}
+}
+
+// DESC 用于 ORDER BY，按照标量子查询的结果排序
+func (s Subquery) DESC() OrderBy {
+	return OrderBy{expr: s, order: "DESC"}
+}
+
+// selected 作为标量子查询出现在 SELECT 部分，别名不为空的时候会输出 AS 别名
+func (Subquery) selected() {}
+
 func (s Subquery) C(name string) Column {
 	return Column{
 		table: s.entity,
@@ -226,3 +327,39 @@ func (s Subquery) RightJoin(target TableReference) *JoinBuilder {
 		typ:   "RIGHT JOIN",
 	}
 }
+
+// FullOuterJoin 即 FULL OUTER JOIN，MySQL 不支持
+func (s Subquery) FullOuterJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  s,
+		right: right,
+		typ:   dialect.JoinFullOuter,
+	}
+}
+
+// StraightJoin 即 STRAIGHT_JOIN，只有 MySQL 支持
+func (s Subquery) StraightJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  s,
+		right: right,
+		typ:   dialect.JoinStraight,
+	}
+}
+
+// CrossJoin 即 CROSS JOIN，不需要 ON 或者 USING
+func (s Subquery) CrossJoin(right TableReference) Join {
+	return Join{
+		left:  s,
+		right: right,
+		typ:   "CROSS JOIN",
+	}
+}
+
+// NaturalJoin 即 NATURAL JOIN，使用两边所有同名的列作为连接条件
+func (s Subquery) NaturalJoin(right TableReference) Join {
+	return Join{
+		left:  s,
+		right: right,
+		typ:   "NATURAL JOIN",
+	}
+}
@@ -29,6 +29,8 @@ type Updater[T any] struct {
 	Session
 	updaterBuilder
 	table interface{}
+	// target 是 UPDATE 的目标，为 nil 的时候就是 T 对应的表
+	target TableReference
 }
 
 // NewUpdater 开始构建一个 UPDATE 查询
@@ -49,6 +51,14 @@ func (u *Updater[T]) Update(val *T) *Updater[T] {
 	return u
 }
 
+// Table 指定 UPDATE 的目标，可以是带别名的 Table，也可以是 JOIN，
+// 例如 UPDATE (a JOIN b ON ...) SET a.x = b.y，此时必须使用 Table.C(...).Assign 来赋值
+// 并不是所有的方言都支持 JOIN，例如 PostgreSQL 就不支持
+func (u *Updater[T]) Table(table TableReference) *Updater[T] {
+	u.target = table
+	return u
+}
+
 // Build returns UPDATE query
 func (u *Updater[T]) Build() (Query, error) {
 	defer bytebufferpool.Put(u.buffer)
@@ -65,9 +75,23 @@ func (u *Updater[T]) Build() (Query, error) {
 	u.val = u.valCreator.NewPrimitiveValue(u.table, u.meta)
 	u.args = make([]interface{}, 0, len(u.meta.Columns))
 
+	// 软删除的条件可能会放在 JOIN 的 ON 里面，所以要在构造目标之前计算
+	target, where := u.target, u.where
+	if !u.unscoped {
+		if target, where, err = u.withNotDeleted(u.target, where); err != nil {
+			return EmptyQuery, err
+		}
+	}
 	u.writeString("UPDATE ")
-	u.quote(u.meta.TableName)
+	if err = u.buildTarget(target); err != nil {
+		return EmptyQuery, err
+	}
 	u.writeString(" SET ")
+	if _, ok := u.target.(Join); ok {
+		if err = u.checkJoinAssigns(); err != nil {
+			return EmptyQuery, err
+		}
+	}
 	if len(u.assigns) == 0 {
 		err = u.buildDefaultColumns()
 	} else {
@@ -77,14 +101,20 @@ func (u *Updater[T]) Build() (Query, error) {
 		return EmptyQuery, err
 	}
 
-	if len(u.where) > 0 {
+	if len(where) > 0 {
 		u.writeString(" WHERE ")
-		err = u.buildPredicates(u.where)
+		err = u.buildPredicates(where)
 		if err != nil {
 			return EmptyQuery, err
 		}
 	}
 
+	if len(u.returning) > 0 {
+		if err = u.buildReturning(u.returning); err != nil {
+			return EmptyQuery, err
+		}
+	}
+
 	u.end()
 	return Query{
 		SQL:  u.buffer.String(),
@@ -92,8 +122,46 @@ func (u *Updater[T]) Build() (Query, error) {
 	}, nil
 }
 
+func (u *Updater[T]) buildTarget(target TableReference) error {
+	switch tbl := target.(type) {
+	case nil:
+		u.quote(u.meta.TableName)
+		return nil
+	case Table:
+		return u.buildTableReference(tbl)
+	case Join:
+		if !u.dialect.SupportJoinMutation() {
+			return errs.NewUnsupportedClauseError(u.dialect.Name(), "UPDATE JOIN")
+		}
+		return u.buildTableReference(tbl)
+	default:
+		return errs.NewUnsupportedTableReferenceError(tbl)
+	}
+}
+
+// checkJoinAssigns 检查多表更新的 SET 子句。
+// 多表更新的时候无法根据 T 的元数据判断列属于哪个表，
+// 所以要求用户必须通过 Table.C(...).Assign(...) 明确指定
+func (u *Updater[T]) checkJoinAssigns() error {
+	if len(u.assigns) == 0 {
+		return errs.ErrUpdateJoinUnqualifiedColumn
+	}
+	for _, assign := range u.assigns {
+		switch a := assign.(type) {
+		case Column, columns:
+			return errs.ErrUpdateJoinUnqualifiedColumn
+		case Assignment:
+			if c, ok := a.left.(Column); ok && c.table == nil {
+				return errs.ErrUpdateJoinUnqualifiedColumn
+			}
+		}
+	}
+	return nil
+}
+
 func (u *Updater[T]) buildAssigns() error {
 	has := false
+	now := u.now()
 	for _, assign := range u.assigns {
 		if has {
 			u.comma()
@@ -104,10 +172,9 @@ func (u *Updater[T]) buildAssigns() error {
 			if !ok {
 				return errs.NewInvalidFieldError(a.name)
 			}
-			refVal, _ := u.val.Field(a.name)
 			u.quote(c.ColumnName)
 			_ = u.buffer.WriteByte('=')
-			u.parameter(refVal.Interface())
+			u.parameter(u.assignValue(c, now))
 			has = true
 		case columns:
 			for _, name := range a.cs {
@@ -115,13 +182,12 @@ func (u *Updater[T]) buildAssigns() error {
 				if !ok {
 					return errs.NewInvalidFieldError(name)
 				}
-				refVal, _ := u.val.Field(name)
 				if has {
 					u.comma()
 				}
 				u.quote(c.ColumnName)
 				_ = u.buffer.WriteByte('=')
-				u.parameter(refVal.Interface())
+				u.parameter(u.assignValue(c, now))
 				has = true
 			}
 		case Assignment:
@@ -136,17 +202,25 @@ func (u *Updater[T]) buildAssigns() error {
 	if !has {
 		return errs.NewValueNotSetError()
 	}
+	// 多表更新的时候无法确定列属于哪个表，所以不会自动更新时间
+	if _, ok := u.target.(Join); ok {
+		return nil
+	}
+	for _, c := range u.autoUpdateColumns(u.meta) {
+		u.comma()
+		u.quote(c.ColumnName)
+		_ = u.buffer.WriteByte('=')
+		u.parameter(c.TimeValue(now))
+	}
 	return nil
 }
 
 func (u *Updater[T]) buildDefaultColumns() error {
 	has := false
+	now := u.now()
 	for _, c := range u.meta.Columns {
-		refVal, _ := u.val.Field(c.FieldName)
-		if u.ignoreZeroVal && isZeroValue(refVal) {
-			continue
-		}
-		if u.ignoreNilVal && isNilValue(refVal) {
+		val, ok := u.defaultColumnValue(c, now)
+		if !ok {
 			continue
 		}
 		if has {
@@ -154,7 +228,7 @@ func (u *Updater[T]) buildDefaultColumns() error {
 		}
 		u.quote(c.ColumnName)
 		_ = u.buffer.WriteByte('=')
-		u.parameter(refVal.Interface())
+		u.parameter(val)
 		has = true
 	}
 	if !has {
@@ -169,12 +243,25 @@ func (u *Updater[T]) Set(assigns ...Assignable) *Updater[T] {
 	return u
 }
 
+// Unscoped 被软删除的数据也会被更新
+func (u *Updater[T]) Unscoped() *Updater[T] {
+	u.unscoped = true
+	return u
+}
+
 // Where represents WHERE clause
 func (u *Updater[T]) Where(predicates ...Predicate) *Updater[T] {
 	u.where = predicates
 	return u
 }
 
+// Returning 指定 RETURNING 子句中返回的字段
+// 并不是所有的方言都支持 RETURNING，例如 MySQL 就不支持
+func (u *Updater[T]) Returning(fields ...string) *Updater[T] {
+	u.returning = fields
+	return u
+}
+
 // SkipNilValue 忽略 nil 值 columns
 func (u *Updater[T]) SkipNilValue() *Updater[T] {
 	u.ignoreNilVal = true
@@ -14,7 +14,12 @@
 
 package eorm
 
-import "github.com/ecodeclub/eorm/internal/valuer"
+import (
+	"time"
+
+	"github.com/ecodeclub/eorm/internal/model"
+	"github.com/ecodeclub/eorm/internal/valuer"
+)
 
 type updaterBuilderAttribute struct {
 	val           valuer.Value
@@ -22,6 +27,9 @@ type updaterBuilderAttribute struct {
 	assigns       []Assignable
 	ignoreNilVal  bool
 	ignoreZeroVal bool
+	returning     []string
+	// unscoped 为 true 的时候，被软删除的数据也会被更新
+	unscoped bool
 }
 
 type updaterBuilder struct {
@@ -33,3 +41,57 @@ type shardingUpdaterBuilder struct {
 	shardingBuilder
 	updaterBuilderAttribute
 }
+
+// defaultColumnValue 返回没有调用 Set 的时候，列 c 需要更新的值，返回 false 代表跳过该列
+func (u updaterBuilderAttribute) defaultColumnValue(c *model.ColumnMeta, now time.Time) (any, bool) {
+	if c.AutoUpdateTime {
+		return c.TimeValue(now), true
+	}
+	refVal, _ := u.val.Field(c.FieldName)
+	// 零值的创建时间不能覆盖数据库中已有的值
+	if c.AutoCreateTime && isZeroValue(refVal) {
+		return nil, false
+	}
+	if u.ignoreZeroVal && isZeroValue(refVal) {
+		return nil, false
+	}
+	if u.ignoreNilVal && isNilValue(refVal) {
+		return nil, false
+	}
+	return refVal.Interface(), true
+}
+
+// assignValue 返回 Set 的列 c 的值，autoUpdateTime 列总是使用当前时间
+func (u updaterBuilderAttribute) assignValue(c *model.ColumnMeta, now time.Time) any {
+	if c.AutoUpdateTime {
+		return c.TimeValue(now)
+	}
+	refVal, _ := u.val.Field(c.FieldName)
+	return refVal.Interface()
+}
+
+// autoUpdateColumns 返回没有出现在 Set 中的 autoUpdateTime 列
+func (u updaterBuilderAttribute) autoUpdateColumns(meta *model.TableMeta) []*model.ColumnMeta {
+	assigned := make(map[string]struct{}, len(u.assigns))
+	for _, assign := range u.assigns {
+		switch a := assign.(type) {
+		case Column:
+			assigned[a.name] = struct{}{}
+		case columns:
+			for _, name := range a.cs {
+				assigned[name] = struct{}{}
+			}
+		case Assignment:
+			if c, ok := a.left.(Column); ok {
+				assigned[c.name] = struct{}{}
+			}
+		}
+	}
+	var res []*model.ColumnMeta
+	for _, c := range meta.Columns {
+		if _, ok := assigned[c.FieldName]; c.AutoUpdateTime && !ok {
+			res = append(res, c)
+		}
+	}
+	return res
+}
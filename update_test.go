@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ecodeclub/eorm/internal/datasource/single"
 
@@ -38,6 +39,9 @@ func TestUpdater_Set(t *testing.T) {
 		LastName:  &sql.NullString{String: "Jerry", Valid: true},
 	}
 	db := memoryDB()
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
+	sub := NewSelector[TestModel](db).AsSubquery("sub")
 	testCases := []CommonTestCase{
 		{
 			name:     "no set and update",
@@ -124,6 +128,93 @@ func TestUpdater_Set(t *testing.T) {
 			wantSql:  "UPDATE `test_model` SET `id`=?;",
 			wantArgs: []interface{}{int64(13)},
 		},
+		{
+			name:     "postgres",
+			builder:  NewUpdater[TestModel](postgresDB(t)).Update(tm).Set(Columns("FirstName"), Assign("Age", C("Age").Add(1))).Where(C("Id").EQ(12)),
+			wantSql:  `UPDATE "test_model" SET "first_name"=$1,"age"=("age"+$2) WHERE "id"=$3;`,
+			wantArgs: []interface{}{"Tom", 1, 12},
+		},
+		{
+			name:     "returning",
+			builder:  NewUpdater[TestModel](postgresDB(t)).Update(tm).Set(Columns("Age")).Where(C("Id").EQ(12)).Returning("Id", "Age"),
+			wantSql:  `UPDATE "test_model" SET "age"=$1 WHERE "id"=$2 RETURNING "id","age";`,
+			wantArgs: []interface{}{int8(18), 12},
+		},
+		{
+			name: "table with alias",
+			builder: NewUpdater[TestModel](db).Update(tm).Table(TableOf(&TestModel{}, "t1")).
+				Set(TableOf(&TestModel{}, "t1").C("Age").Assign(20)).Where(C("Id").EQ(12)),
+			wantSql:  "UPDATE `test_model` AS `t1` SET `t1`.`age`=? WHERE `id`=?;",
+			wantArgs: []interface{}{20, 12},
+		},
+		{
+			name: "join",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewUpdater[TestModel](mysqlDB).
+					Table(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age")), t2.C("FirstName").EQ("Tom"))).
+					Set(t1.C("FirstName").Assign(t2.C("FirstName")), t1.C("Age").Assign(t2.C("Age").Add(1))).
+					Where(t1.C("Id").LT(100))
+			}(),
+			wantSql:  "UPDATE (`test_model` AS `t1` JOIN `test_model` AS `t2` ON (`t1`.`id`=`t2`.`age`) AND (`t2`.`first_name`=?)) SET `t1`.`first_name`=`t2`.`first_name`,`t1`.`age`=(`t2`.`age`+?) WHERE `t1`.`id`<?;",
+			wantArgs: []interface{}{"Tom", 1, 100},
+		},
+		{
+			name: "join with unqualified assignment",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewUpdater[TestModel](mysqlDB).Table(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age")))).
+					Set(t1.C("FirstName").Assign("Tom"), Assign("Age", 1))
+			}(),
+			wantErr: errs.ErrUpdateJoinUnqualifiedColumn,
+		},
+		{
+			name: "join with column",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewUpdater[TestModel](mysqlDB).Update(&TestModel{Age: 18}).
+					Table(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age")))).Set(C("Age"))
+			}(),
+			wantErr: errs.ErrUpdateJoinUnqualifiedColumn,
+		},
+		{
+			name: "join with columns",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewUpdater[TestModel](mysqlDB).Update(&TestModel{Age: 18}).
+					Table(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age")))).Set(Columns("Age", "FirstName"))
+			}(),
+			wantErr: errs.ErrUpdateJoinUnqualifiedColumn,
+		},
+		{
+			name: "join without set",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewUpdater[TestModel](mysqlDB).Update(&TestModel{Age: 18}).
+					Table(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age"))))
+			}(),
+			wantErr: errs.ErrUpdateJoinUnqualifiedColumn,
+		},
+		{
+			name: "join unsupported",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				t2 := TableOf(&TestModel{}, "t2")
+				return NewUpdater[TestModel](postgresDB(t)).Table(t1.Join(t2).On(t1.C("Id").EQ(t2.C("Age")))).
+					Set(t1.C("Age").Assign(t2.C("Age")))
+			}(),
+			wantErr: errs.NewUnsupportedClauseError("PostgreSQL", "UPDATE JOIN"),
+		},
+		{
+			name:    "subquery unsupported",
+			builder: NewUpdater[TestModel](db).Table(sub).Set(Assign("Age", 1)),
+			wantErr: errs.NewUnsupportedTableReferenceError(sub),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -244,6 +335,67 @@ func TestUpdater_SetForCombination(t *testing.T) {
 	}
 }
 
+func TestUpdater_AutoTime(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	db, err := Open("sqlite3", "file:test.db?cache=shared&mode=memory",
+		DBWithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name:     "default columns",
+			builder:  NewUpdater[autoTimeModel](db).Update(&autoTimeModel{Id: 1, Name: "Tom", UpdateTime: 100}),
+			wantSql:  "UPDATE `auto_time_model` SET `id`=?,`name`=?,`update_time`=?;",
+			wantArgs: []interface{}{1, "Tom", int64(1700000000123)},
+		},
+		{
+			name:     "default columns with create time",
+			builder:  NewUpdater[autoTimeModel](db).Update(&autoTimeModel{Id: 1, Name: "Tom", CreateTime: 100}),
+			wantSql:  "UPDATE `auto_time_model` SET `id`=?,`name`=?,`create_time`=?,`update_time`=?;",
+			wantArgs: []interface{}{1, "Tom", int64(100), int64(1700000000123)},
+		},
+		{
+			name: "skip zero value",
+			builder: NewUpdater[autoTimeModel](db).Update(&autoTimeModel{Name: "Tom"}).
+				SkipZeroValue().Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `auto_time_model` SET `name`=?,`update_time`=? WHERE `id`=?;",
+			wantArgs: []interface{}{"Tom", int64(1700000000123), 1},
+		},
+		{
+			name: "set",
+			builder: NewUpdater[autoTimeModel](db).Update(&autoTimeModel{Name: "Tom"}).
+				Set(C("Name")).Where(C("Id").EQ(1)),
+			wantSql:  "UPDATE `auto_time_model` SET `name`=?,`update_time`=? WHERE `id`=?;",
+			wantArgs: []interface{}{"Tom", int64(1700000000123), 1},
+		},
+		{
+			name: "set update time",
+			builder: NewUpdater[autoTimeModel](db).Update(&autoTimeModel{Name: "Tom", UpdateTime: 100}).
+				Set(Columns("Name", "UpdateTime")),
+			wantSql:  "UPDATE `auto_time_model` SET `name`=?,`update_time`=?;",
+			wantArgs: []interface{}{"Tom", int64(1700000000123)},
+		},
+		{
+			name:     "assign update time",
+			builder:  NewUpdater[autoTimeModel](db).Set(Assign("Name", "Tom"), Assign("UpdateTime", 100)),
+			wantSql:  "UPDATE `auto_time_model` SET `name`=?,`update_time`=?;",
+			wantArgs: []interface{}{"Tom", 100},
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			query, err := tc.builder.Build()
+			assert.Equal(t, err, c.wantErr)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, query.SQL)
+			assert.Equal(t, c.wantArgs, query.Args)
+		})
+	}
+}
+
 func TestUpdater_Exec(t *testing.T) {
 	tm := &TestModel{
 		Id:        12,
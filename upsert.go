@@ -0,0 +1,110 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import "github.com/ecodeclub/eorm/internal/errs"
+
+// Upsert 代表 INSERT 语句中冲突之后的更新部分
+// 在 MySQL 里面是 ON DUPLICATE KEY UPDATE
+// 在 SQLite 和 PostgreSQL 里面是 ON CONFLICT(xxx) DO UPDATE SET
+type Upsert struct {
+	// conflictColumns 是冲突列的字段名
+	conflictColumns []string
+	assigns         []Assignable
+}
+
+// OnDuplicateKeyBuilder 用于构造 Upsert，
+// 泛型参数 I 是构造完毕之后返回的 Inserter 类型
+type OnDuplicateKeyBuilder[I any] struct {
+	inserter        I
+	attr            *inserterBuilderAttribute
+	conflictColumns []string
+}
+
+// Update 指定冲突之后需要更新的列
+// 如果传入的是 C 或者 Columns，那么会使用待插入的值来更新，
+// 例如 MySQL 中的 `col`=VALUES(`col`)，SQLite 中的 `col`=excluded.`col`
+// 如果传入的是 Assign，那么会使用指定的值或者表达式来更新
+func (o *OnDuplicateKeyBuilder[I]) Update(assigns ...Assignable) I {
+	o.attr.upsert = &Upsert{
+		conflictColumns: o.conflictColumns,
+		assigns:         assigns,
+	}
+	return o.inserter
+}
+
+func (b *builder) buildUpsert(upsert *Upsert) error {
+	cs := make([]string, 0, len(upsert.conflictColumns))
+	for _, name := range upsert.conflictColumns {
+		fd, ok := b.meta.FieldMap[name]
+		if !ok {
+			return errs.NewInvalidFieldError(name)
+		}
+		cs = append(cs, fd.ColumnName)
+	}
+	if err := b.dialect.BuildUpsert(dialectWriter{b: b}, cs); err != nil {
+		return err
+	}
+	has := false
+	for _, assign := range upsert.assigns {
+		switch a := assign.(type) {
+		case Column:
+			if has {
+				b.comma()
+			}
+			if err := b.buildUpsertColumn(a.name); err != nil {
+				return err
+			}
+		case columns:
+			if len(a.cs) == 0 {
+				continue
+			}
+			for i, name := range a.cs {
+				if has || i > 0 {
+					b.comma()
+				}
+				if err := b.buildUpsertColumn(name); err != nil {
+					return err
+				}
+			}
+		case Assignment:
+			if has {
+				b.comma()
+			}
+			if err := b.buildExpr(binaryExpr(a)); err != nil {
+				return err
+			}
+		default:
+			return errs.ErrUnsupportedAssignment
+		}
+		has = true
+	}
+	if !has {
+		return errs.NewValueNotSetError()
+	}
+	return nil
+}
+
+// buildUpsertColumn 使用待插入的值来更新 name 对应的列
+func (b *builder) buildUpsertColumn(name string) error {
+	fd, ok := b.meta.FieldMap[name]
+	if !ok {
+		return errs.NewInvalidFieldError(name)
+	}
+	b.quote(fd.ColumnName)
+	b.writeByte('=')
+	b.writeString(b.dialect.InsertedValue(fd.ColumnName))
+	return nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import "strconv"
+
+// Window 代表窗口函数，例如 ROW_NUMBER() OVER (PARTITION BY ... ORDER BY ...)
+// MySQL 从 8.0 开始支持，SQLite 从 3.25.0 开始支持
+type Window struct {
+	table       TableReference
+	fn          string
+	arg         string
+	distinct    bool
+	offset      int
+	partitionBy []string
+	orderBy     []OrderBy
+	frame       *windowFrame
+	alias       string
+}
+
+type windowFrame struct {
+	// typ 是 ROWS 或者 RANGE
+	typ   string
+	start FrameBound
+	end   FrameBound
+}
+
+// FrameBound 代表窗口的边界
+type FrameBound struct {
+	n   int
+	typ string
+}
+
+// UnboundedPreceding 即 UNBOUNDED PRECEDING，从分区的第一行开始
+func UnboundedPreceding() FrameBound {
+	return FrameBound{typ: "UNBOUNDED PRECEDING"}
+}
+
+// Preceding 即 n PRECEDING，当前行之前的第 n 行
+func Preceding(n int) FrameBound {
+	return FrameBound{n: n, typ: "PRECEDING"}
+}
+
+// CurrentRow 即 CURRENT ROW
+func CurrentRow() FrameBound {
+	return FrameBound{typ: "CURRENT ROW"}
+}
+
+// Following 即 n FOLLOWING，当前行之后的第 n 行
+func Following(n int) FrameBound {
+	return FrameBound{n: n, typ: "FOLLOWING"}
+}
+
+// UnboundedFollowing 即 UNBOUNDED FOLLOWING，到分区的最后一行结束
+func UnboundedFollowing() FrameBound {
+	return FrameBound{typ: "UNBOUNDED FOLLOWING"}
+}
+
+// RowNumber represents ROW_NUMBER()
+func RowNumber() Window {
+	return Window{fn: "ROW_NUMBER"}
+}
+
+// Rank represents RANK()
+func Rank() Window {
+	return Window{fn: "RANK"}
+}
+
+// DenseRank represents DENSE_RANK()
+func DenseRank() Window {
+	return Window{fn: "DENSE_RANK"}
+}
+
+// Lag represents LAG(col, offset)，即当前行之前第 offset 行的值
+func Lag(c string, offset int) Window {
+	return Window{fn: "LAG", arg: c, offset: offset}
+}
+
+// Lead represents LEAD(col, offset)，即当前行之后第 offset 行的值
+func Lead(c string, offset int) Window {
+	return Window{fn: "LEAD", arg: c, offset: offset}
+}
+
+// Over 将聚合函数作为窗口函数使用，例如 SUM(`amount`) OVER (...)
+func (a Aggregate) Over() Window {
+	return Window{
+		table:    a.table,
+		fn:       a.fn,
+		arg:      a.arg,
+		distinct: a.distinct,
+	}
+}
+
+// PartitionBy 即 PARTITION BY，fields 是字段名
+func (w Window) PartitionBy(fields ...string) Window {
+	w.partitionBy = fields
+	return w
+}
+
+// OrderBy 即窗口中的 ORDER BY
+func (w Window) OrderBy(orderBys ...OrderBy) Window {
+	w.orderBy = orderBys
+	return w
+}
+
+// Rows 即 ROWS BETWEEN start AND end
+func (w Window) Rows(start, end FrameBound) Window {
+	w.frame = &windowFrame{typ: "ROWS", start: start, end: end}
+	return w
+}
+
+// Range 即 RANGE BETWEEN start AND end
+func (w Window) Range(start, end FrameBound) Window {
+	w.frame = &windowFrame{typ: "RANGE", start: start, end: end}
+	return w
+}
+
+// As 指定别名，结果集会按照别名映射到对应的字段
+func (w Window) As(alias string) Selectable {
+	w.alias = alias
+	return w
+}
+
+func (Window) selected() {}
+
+func (Window) expr() (string, error) {
+	return "", nil
+}
+
+// ASC 用于 ORDER BY，例如 ORDER BY ROW_NUMBER() OVER (...) ASC
+func (w Window) ASC() OrderBy {
+	return OrderBy{expr: w, order: "ASC"}
+}
+
+// DESC 用于 ORDER BY，例如 ORDER BY ROW_NUMBER() OVER (...) DESC
+func (w Window) DESC() OrderBy {
+	return OrderBy{expr: w, order: "DESC"}
+}
+
+func (b *builder) buildWindow(w Window) error {
+	b.writeString(w.fn)
+	b.writeByte('(')
+	if w.arg != "" {
+		if w.distinct {
+			b.writeString("DISTINCT ")
+		}
+		if err := b.buildColumn(Column{table: w.table, name: w.arg}); err != nil {
+			return err
+		}
+		if w.offset > 0 {
+			b.comma()
+			b.writeString(strconv.Itoa(w.offset))
+		}
+	}
+	b.writeString(") OVER (")
+	hasClause := false
+	if len(w.partitionBy) > 0 {
+		b.writeString("PARTITION BY ")
+		for i, f := range w.partitionBy {
+			if i > 0 {
+				b.comma()
+			}
+			if err := b.buildColumn(Column{table: w.table, name: f}); err != nil {
+				return err
+			}
+		}
+		hasClause = true
+	}
+	if len(w.orderBy) > 0 {
+		if hasClause {
+			b.space()
+		}
+		b.writeString("ORDER BY ")
+		if err := b.buildWindowOrderBy(w); err != nil {
+			return err
+		}
+		hasClause = true
+	}
+	if w.frame != nil {
+		if hasClause {
+			b.space()
+		}
+		b.writeString(w.frame.typ)
+		b.writeString(" BETWEEN ")
+		b.buildFrameBound(w.frame.start)
+		b.writeString(" AND ")
+		b.buildFrameBound(w.frame.end)
+	}
+	b.writeByte(')')
+	if w.alias != "" {
+		b.writeString(" AS ")
+		b.quote(w.alias)
+	}
+	return nil
+}
+
+// buildWindowOrderBy 构造 OVER 中的 ORDER BY，
+// 没有指定表的列和字段名一样，都使用窗口函数所在的表
+func (b *builder) buildWindowOrderBy(w Window) error {
+	for i, ob := range w.orderBy {
+		if i > 0 {
+			b.comma()
+		}
+		if c, ok := ob.expr.(Column); ok && c.table == nil {
+			ob.fields, ob.expr = []string{c.name}, nil
+		}
+		if ob.expr != nil {
+			expr := ob.expr
+			if err := b.buildOrderByItem(ob, func() error {
+				return b.buildSubExpr(expr)
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		for j, f := range ob.fields {
+			if j > 0 {
+				b.comma()
+			}
+			c := Column{table: w.table, name: f}
+			if err := b.buildOrderByItem(ob, func() error {
+				return b.buildColumn(c)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *builder) buildFrameBound(fb FrameBound) {
+	if fb.typ == "PRECEDING" || fb.typ == "FOLLOWING" {
+		b.writeString(strconv.Itoa(fb.n))
+		b.space()
+	}
+	b.writeString(fb.typ)
+}
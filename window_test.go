@@ -0,0 +1,133 @@
+// Copyright 2021 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eorm
+
+import (
+	"testing"
+
+	"github.com/ecodeclub/eorm/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindow(t *testing.T) {
+	db := memoryDB()
+	mysqlDB, err := Open("mysql", "root:root@tcp(localhost:3306)/test")
+	require.NoError(t, err)
+	testCases := []CommonTestCase{
+		{
+			name: "row number",
+			builder: NewSelector[TestModel](db).Select(C("Id"),
+				RowNumber().PartitionBy("FirstName").OrderBy(DESC("Age")).As("rn")),
+			wantSql: "SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `first_name` ORDER BY `age` DESC) AS `rn` FROM `test_model`;",
+		},
+		{
+			name:    "rank without clause",
+			builder: NewSelector[TestModel](db).Select(Rank().OrderBy(ASC("Age")), DenseRank()),
+			wantSql: "SELECT RANK() OVER (ORDER BY `age` ASC),DENSE_RANK() OVER () FROM `test_model`;",
+		},
+		{
+			name: "running total",
+			builder: NewSelector[TestModel](mysqlDB).Select(C("Id"),
+				Sum("Age").Over().PartitionBy("FirstName", "LastName").OrderBy(ASC("Id")).
+					Rows(UnboundedPreceding(), CurrentRow()).As("total")).Where(C("Age").GT(18)),
+			wantSql:  "SELECT `id`,SUM(`age`) OVER (PARTITION BY `first_name`,`last_name` ORDER BY `id` ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS `total` FROM `test_model` WHERE `age`>?;",
+			wantArgs: []interface{}{18},
+		},
+		{
+			name: "range frame",
+			builder: NewSelector[TestModel](db).Select(
+				Avg("Age").Over().OrderBy(ASC("Id")).Range(Preceding(2), Following(1))),
+			wantSql: "SELECT AVG(`age`) OVER (ORDER BY `id` ASC RANGE BETWEEN 2 PRECEDING AND 1 FOLLOWING) FROM `test_model`;",
+		},
+		{
+			name: "frame only",
+			builder: NewSelector[TestModel](db).Select(
+				CountDistinct("Age").Over().Rows(CurrentRow(), UnboundedFollowing())),
+			wantSql: "SELECT COUNT(DISTINCT `age`) OVER (ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING) FROM `test_model`;",
+		},
+		{
+			name: "lag and lead",
+			builder: NewSelector[TestModel](db).Select(
+				Lag("Age", 1).OrderBy(ASC("Id")).As("prev_age"),
+				Lead("Age", 2).PartitionBy("FirstName").OrderBy(ASC("Id")).As("next_age")),
+			wantSql: "SELECT LAG(`age`,1) OVER (ORDER BY `id` ASC) AS `prev_age`,LEAD(`age`,2) OVER (PARTITION BY `first_name` ORDER BY `id` ASC) AS `next_age` FROM `test_model`;",
+		},
+		{
+			name: "with table",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				return NewSelector[TestModel](db).Select(t1.Max("Age").Over().PartitionBy("FirstName")).From(t1)
+			}(),
+			wantSql: "SELECT MAX(`t1`.`age`) OVER (PARTITION BY `t1`.`first_name`) FROM `test_model` AS `t1`;",
+		},
+		{
+			name:    "order by expression",
+			builder: NewSelector[TestModel](db).Select(RowNumber().OrderBy(C("Age").DESC(), Lower(C("FirstName")).ASC())),
+			wantSql: "SELECT ROW_NUMBER() OVER (ORDER BY `age` DESC,LOWER(`first_name`) ASC) FROM `test_model`;",
+		},
+		{
+			name: "order by column with table",
+			builder: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}, "t1")
+				return NewSelector[TestModel](db).Select(RowNumber().OrderBy(t1.C("Age").DESC(), ASC("Id"))).From(t1)
+			}(),
+			wantSql: "SELECT ROW_NUMBER() OVER (ORDER BY `t1`.`age` DESC,`id` ASC) FROM `test_model` AS `t1`;",
+		},
+		{
+			name:    "order by nulls",
+			builder: NewSelector[TestModel](db).Select(RowNumber().OrderBy(DESC("LastName").NullsLast())),
+			wantSql: "SELECT ROW_NUMBER() OVER (ORDER BY `last_name` DESC NULLS LAST) FROM `test_model`;",
+		},
+		{
+			name:    "mysql order by nulls",
+			builder: NewSelector[TestModel](mysqlDB).Select(Rank().OrderBy(C("LastName").ASC().NullsFirst())),
+			wantSql: "SELECT RANK() OVER (ORDER BY `last_name` IS NULL DESC,`last_name` ASC) FROM `test_model`;",
+		},
+		{
+			name:    "invalid arg",
+			builder: NewSelector[TestModel](db).Select(Lag("Invalid", 1)),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid partition by",
+			builder: NewSelector[TestModel](db).Select(RowNumber().PartitionBy("Invalid")),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid order by",
+			builder: NewSelector[TestModel](db).Select(RowNumber().OrderBy(ASC("Invalid"))),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+		{
+			name:    "invalid order by expression",
+			builder: NewSelector[TestModel](db).Select(RowNumber().OrderBy(C("Invalid").DESC())),
+			wantErr: errs.NewInvalidFieldError("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		c := tc
+		t.Run(c.name, func(t *testing.T) {
+			q, err := c.builder.Build()
+			assert.Equal(t, c.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, c.wantSql, q.SQL)
+			assert.Equal(t, c.wantArgs, q.Args)
+		})
+	}
+}